@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/controller"
+	"k8s.io/klog/v2"
+)
+
+// fence: Revoke all array access of a failed node, printing each action taken as a JSON line for auditing. Run it in
+// the controller container so that the initiators recorded when publishing volumes to the node are found.
+func fence(args []string) int {
+	flags := flag.NewFlagSet("fence", flag.ExitOnError)
+	nodeID := flags.String("node-id", "", "ID of the node to fence, as reported by kubectl get csinode")
+	initiators := flags.String("initiators", "", "comma separated initiators of the node, in addition to the recorded ones")
+	apiAddress := flags.String("api-address", "", "comma separated addresses of the array management controllers")
+	username := flags.String("username", "", "array username")
+	passwordFile := flags.String("password-file", "", "file holding the array password, - to read it from stdin")
+	runPath := flags.String("run-path", fmt.Sprintf("/var/run/%s", common.PluginName), "directory holding the controller publish records outside of Kubernetes")
+	deleteHost := flags.Bool("delete-host", false, "delete the array host holding the initiators once its volumes are unmapped")
+	dryRun := flags.Bool("dry-run", false, "report the mappings which would be removed without changing the array")
+	klog.InitFlags(flags)
+	flags.Set("logtostderr", "true")
+	flags.Parse(args)
+
+	if *nodeID == "" || *apiAddress == "" || *username == "" || *passwordFile == "" {
+		fmt.Fprintln(os.Stderr, "fence: -node-id, -api-address, -username and -password-file are required")
+		flags.Usage()
+		return 2
+	}
+
+	password, err := readPassword(*passwordFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fence: unable to read password: %v\n", err)
+		return 1
+	}
+
+	client := storageapi.NewClient()
+	client.StoreCredentials(strings.Split(*apiAddress, ","), "", *username, password)
+	if err := client.Login(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "fence: unable to log in to the array: %v\n", err)
+		return 1
+	}
+	defer client.Logout()
+
+	options := controller.FenceOptions{NodeID: *nodeID, DeleteHost: *deleteHost, DryRun: *dryRun}
+	if *initiators != "" {
+		options.Initiators = strings.Split(*initiators, ",")
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	actions, err := controller.FenceNode(client, controller.NewRecordStore(*runPath), options, func(action controller.FenceAction) {
+		encoder.Encode(action)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fence: %v\n", err)
+		return 1
+	}
+	for _, action := range actions {
+		if action.Result == controller.FenceResultFailed {
+			return 1
+		}
+	}
+	return 0
+}
+
+// readPassword: Read the password from a file or stdin, ignoring the trailing newline
+func readPassword(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return strings.TrimRight(string(data), "\r\n"), err
+}
@@ -7,11 +7,13 @@ import (
 
 	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
 	"github.com/Seagate/seagate-exos-x-csi/pkg/node"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/storage"
 	"k8s.io/klog/v2"
 )
 
 var bind = flag.String("bind", fmt.Sprintf("unix:///var/run/%s/csi-node.sock", common.PluginName), "RPC bind URI (can be a UNIX socket path or any URI)")
 var chroot = flag.String("chroot", "", "Chroot into a directory at startup (used when running in a container)")
+var dryRun = flag.Bool("dryrun", false, "Log the host commands and device operations the node plugin would run instead of running them")
 
 func main() {
 	klog.InitFlags(nil)
@@ -24,6 +26,11 @@ func main() {
 		}
 	}
 
+	if *dryRun {
+		klog.Info("dry-run mode, host commands and device operations will only be logged")
+		storage.UseDryRunStorage()
+	}
+
 	klog.Infof("starting storage node plugin (%s)", common.Version)
 	n := node.New()
 	defer n.Stop()
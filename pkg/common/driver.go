@@ -3,6 +3,7 @@ package common
 import (
 	"context"
 	"net"
+	"net/http"
 	"runtime"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
 )
 
@@ -23,43 +25,105 @@ const PluginName = "csi-exos-x.seagate.com"
 
 // Configuration constants
 const (
-	AugmentKey                = "##"
-	FsTypeConfigKey           = "fsType"
-	PoolConfigKey             = "pool"
-	APIAddressConfigKey       = "apiAddress"
-	APIAddressBConfigKey      = "apiAddressB"
-	UsernameSecretKey         = "username"
-	PasswordSecretKey         = "password"
-	CHAPUsernameKey           = "CHAPusername"
-	CHAPSecretKey             = "CHAPpassword"
-	CHAPUsernameInKey         = "CHAPusernameIn"
-	CHAPPasswordInKey         = "CHAPpasswordIn"
-	StorageClassAnnotationKey = "storageClass"
-	VolumePrefixKey           = "volPrefix"
-	WWNs                      = "wwns"
-	StorageProtocolKey        = "storageProtocol"
-	StorageProtocolISCSI      = "iscsi"
-	StorageProtocolFC         = "fc"
-	StorageProtocolSAS        = "sas"
-	TopologyInitiatorPrefix   = "com.seagate-exos-x-csi"
-	TopologySASInitiatorLabel = "sas-address"
-	TopologyFCInitiatorLabel  = "fc-address"
-	TopologyNodeIdentifier    = "node-id"
-	TopologyNodeIDKey         = TopologyInitiatorPrefix + "/" + TopologyNodeIdentifier
+	AugmentKey                  = "##"
+	FsTypeConfigKey             = "fsType"
+	PoolConfigKey               = "pool"
+	APIAddressConfigKey         = "apiAddress"
+	APIAddressBConfigKey        = "apiAddressB"
+	UsernameSecretKey           = "username"
+	PasswordSecretKey           = "password"
+	CHAPUsernameKey             = "CHAPusername"
+	CHAPSecretKey               = "CHAPpassword"
+	CHAPUsernameInKey           = "CHAPusernameIn"
+	CHAPPasswordInKey           = "CHAPpasswordIn"
+	EncryptedConfigKey          = "encrypted"
+	EncryptionPassphraseKey     = "encryptionPassphrase"
+	StorageClassAnnotationKey   = "storageClass"
+	VolumePrefixKey             = "volPrefix"
+	PVNameConfigKey             = "pvName"
+	MkfsBlockSizeConfigKey      = "mkfsBlockSize"
+	MkfsInodeRatioConfigKey     = "mkfsInodeRatio"
+	MkfsLazyInitConfigKey       = "mkfsLazyInit"
+	MkfsReflinkConfigKey        = "mkfsReflink"
+	FsCheckPolicyConfigKey      = "fsCheckPolicy"
+	FsCheckPolicySkip           = "skip"
+	FsCheckPolicyCheck          = "check"
+	FsCheckPolicyRepair         = "repair"
+	MappingTargetConfigKey      = "mappingTarget"
+	MappingTargetInitiators     = "initiators"
+	MappingTargetHost           = "host"
+	MappingTargetHostGroup      = "hostGroup"
+	MappingHostNameConfigKey    = "hostName"
+	MappingHostGroupConfigKey   = "hostGroupName"
+	MappingLUNRangeConfigKey    = "lunRange"
+	MappingPublishContextKey    = "mapping"
+	WWNs                        = "wwns"
+	StorageProtocolKey          = "storageProtocol"
+	StorageProtocolISCSI        = "iscsi"
+	StorageProtocolFC           = "fc"
+	StorageProtocolSAS          = "sas"
+	TopologyInitiatorPrefix     = "com.seagate-exos-x-csi"
+	TopologySASInitiatorLabel   = "sas-address"
+	TopologyFCInitiatorLabel    = "fc-address"
+	TopologyISCSIInitiatorLabel = "iscsi-initiator"
+	TopologyNodeIdentifier      = "node-id"
+	TopologyNodeIDKey           = TopologyInitiatorPrefix + "/" + TopologyNodeIdentifier
+	TopologyInitiatorCountLabel = "initiators"
+	NodeIdSeparator             = "_"
 
 	MaximumLUN            = 255
 	VolumeNameMaxLength   = 31
 	VolumePrefixMaxLength = 3
 
 	//If changed, must also be updated in helm charts
-	NodeIPEnvVar          = "CSI_NODE_IP"
 	NodeNameEnvVar        = "CSI_NODE_NAME"
 	NodeServicePortEnvVar = "CSI_NODE_SERVICE_PORT"
+	NodeRunPathEnvVar     = "CSI_NODE_RUN_PATH"
+	NodeMaxVolumesEnvVar  = "CSI_NODE_MAX_VOLUMES"
+	// Number of multipath paths expected for the volumes of each protocol, as "iscsi=4,fc=2,sas=2"
+	NodeExpectedPathsEnvVar = "CSI_NODE_EXPECTED_PATHS"
+
+	// Node service mutual TLS, the same variables are used by the controller (client) and the nodes (server)
+	NodeServiceTLSCertEnvVar  = "CSI_NODE_SERVICE_TLS_CERT"
+	NodeServiceTLSKeyEnvVar   = "CSI_NODE_SERVICE_TLS_KEY"
+	NodeServiceTLSCAEnvVar    = "CSI_NODE_SERVICE_TLS_CA"
+	NodeServiceInsecureEnvVar = "CSI_NODE_SERVICE_INSECURE"
+	// Advertise ControllerGetVolume and volume conditions, for the external health monitor
+	VolumeHealthEnvVar = "CSI_CONTROLLER_VOLUME_HEALTH"
+	// How often the controller reads the inventory of the arrays for the exporter, as a duration, 0 to disable
+	InventoryIntervalEnvVar = "CSI_CONTROLLER_INVENTORY_INTERVAL"
+	// Directory of a mounted secret holding the address and credentials of the array, with the keys of the storage
+	// class secrets, read at startup
+	ArraySecretPathEnvVar = "CSI_CONTROLLER_ARRAY_SECRET_PATH"
+	// Names the node service certificates must hold, as common name or DNS name
+	NodeServiceServerName = "seagate-exos-x-csi-node"
+	NodeServiceClientName = "seagate-exos-x-csi-controller"
+	// Version of the node service RPCs, version 1 nodes do not implement GetNodeInfo, version 2 nodes RescanLUN,
+	// version 3 nodes WatchHealth
+	NodeServiceAPIVersion = 4
 )
 
-var SupportedAccessModes = [2]csi.VolumeCapability_AccessMode_Mode{
+var SupportedAccessModes = [3]csi.VolumeCapability_AccessMode_Mode{
 	csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
 	csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+	csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+}
+
+// SupportedFsTypes are the filesystems the node plugin will create, check and expand
+var SupportedFsTypes = []string{"ext3", "ext4", "xfs", "btrfs"}
+
+// AllowedMountFlags are the mount options (without any "=value" part) that may be passed through from
+// VolumeCapability.Mount.MountFlags. Options that change the kind of mount, such as bind or remount, are not allowed.
+var AllowedMountFlags = []string{
+	"ro", "rw",
+	"atime", "noatime", "diratime", "nodiratime", "relatime", "norelatime", "strictatime", "lazytime", "nolazytime",
+	"discard", "nodiscard",
+	"sync", "async", "dirsync",
+	"exec", "noexec", "suid", "nosuid", "dev", "nodev",
+	"barrier", "nobarrier", "commit", "data", "errors", "journal_checksum", "nojournal_checksum",
+	"inode64", "inode32", "logbufs", "logbsize", "largeio", "nolargeio", "allocsize", "noquota",
+	"usrquota", "grpquota", "prjquota", "uquota", "gquota", "pquota",
+	"compress", "compress-force", "space_cache", "ssd", "nossd", "autodefrag", "noautodefrag",
 }
 
 // Driver contains main resources needed by the driver and references the underlying specific driver
@@ -96,15 +160,20 @@ func NewDriver(collectors ...prometheus.Collector) *Driver {
 	return &Driver{exporter: exporter}
 }
 
+// InstrumentHTTPClient: Export the duration and in-flight count of the requests of an HTTP client, by the command
+// returned for each request
+func (driver *Driver) InstrumentHTTPClient(httpClient *http.Client, command func(*http.Request) string) {
+	httpClient.Transport = exporter.NewInstrumentedTransport(httpClient.Transport, driver.exporter.Collector, command)
+}
+
 var routineTimers = map[string]time.Time{}
 
 func (driver *Driver) InitServer(unaryServerInterceptors ...grpc.UnaryServerInterceptor) {
 	interceptors := append([]grpc.UnaryServerInterceptor{
 		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-			start := time.Now()
+			done := driver.exporter.Collector.TrackCSIRPCCall(info.FullMethod)
 			resp, err := handler(ctx, req)
-			driver.exporter.Collector.IncCSIRPCCall(info.FullMethod, err == nil)
-			driver.exporter.Collector.AddCSIRPCCallDuration(info.FullMethod, time.Since(start))
+			done(status.Code(err).String())
 			return resp, err
 		},
 	}, unaryServerInterceptors...)
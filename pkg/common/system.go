@@ -19,10 +19,15 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"regexp"
 	"strings"
 	"unicode"
 
+	"github.com/container-storage-interface/spec/lib/go/csi"
 	"k8s.io/klog/v2"
 )
 
@@ -139,7 +144,116 @@ func VolumeIdAugment(volumename, storageprotocol, wwn string) string {
 	return volumeId
 }
 
-// We use IQN for Node ID, but IQN can contain colons which are not allowed in the topology map
+// We use IQN for Node ID, but IQN can contain colons which are not allowed in the topology map.
+// Node IDs longer than a topology value are shortened with a digest of the whole ID.
 func GetTopologyCompliantNodeID(nodeID string) string {
-	return strings.ReplaceAll(nodeID, ":", ".")
+	value := strings.ReplaceAll(nodeID, ":", ".")
+	if len(value) > 63 {
+		digest := sha256.Sum256([]byte(nodeID))
+		value = strings.TrimRight(value[:46], "-_.") + "-" + hex.EncodeToString(digest[:])[:16]
+	}
+	return value
+}
+
+// NodeIdGetName: Return the node name of a node ID. Node IDs are the node name, which survives IP address and
+// initiator changes. IDs issued before carried a digest of the node initiators after the separator, which Kubernetes
+// node names never contain.
+func NodeIdGetName(nodeId string) string {
+	return strings.SplitN(nodeId, NodeIdSeparator, 2)[0]
+}
+
+// IsLegacyNodeId: Node IDs used to be the IP address of the node, which is also the address of its node service
+func IsLegacyNodeId(nodeId string) bool {
+	return net.ParseIP(nodeId) != nil
+}
+
+// GetTopologyInitiatorLabel: Return the label of the topology segments holding the initiators of a storage protocol
+func GetTopologyInitiatorLabel(storageProtocol string) string {
+	switch storageProtocol {
+	case StorageProtocolSAS:
+		return TopologySASInitiatorLabel
+	case StorageProtocolFC:
+		return TopologyFCInitiatorLabel
+	default:
+		return TopologyISCSIInitiatorLabel
+	}
+}
+
+// GetTopologyInitiatorCountLabel: Return the label of the topology segment holding the number of initiators a node has
+// for a storage protocol, including those which could not be published in its topology
+func GetTopologyInitiatorCountLabel(storageProtocol string) string {
+	return TopologyInitiatorCountLabel + "-" + storageProtocol
+}
+
+// topologyValueRegexp matches the values allowed in topology segments, which are Kubernetes label values
+var topologyValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+
+// GetTopologyCompliantInitiator: IQNs and IPv6 addresses contain colons which are not allowed in topology values but
+// never contain underscores, so colons are swapped for underscores. Returns false if the value cannot be used.
+func GetTopologyCompliantInitiator(initiator string) (string, bool) {
+	value := strings.ReplaceAll(initiator, ":", "_")
+	return value, len(value) <= 63 && topologyValueRegexp.MatchString(value)
+}
+
+// GetInitiatorFromTopology: Return the initiator stored in a topology value by GetTopologyCompliantInitiator
+func GetInitiatorFromTopology(value string) string {
+	return strings.ReplaceAll(value, "_", ":")
+}
+
+// GetTopologyProtocolKey: Return the topology segment key telling whether a node can reach the arrays using a storage protocol
+func GetTopologyProtocolKey(storageProtocol string) string {
+	return TopologyInitiatorPrefix + "/" + storageProtocol
+}
+
+// ValidateMountFlags: Verify that every mount flag is in the list of allowed mount flags
+func ValidateMountFlags(flags []string) error {
+	for _, flag := range flags {
+		for _, option := range strings.Split(flag, ",") {
+			name := strings.TrimSpace(strings.SplitN(option, "=", 2)[0])
+			allowed := false
+			for _, allowedFlag := range AllowedMountFlags {
+				if name == allowedFlag {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return fmt.Errorf("mount flag %q is not allowed", option)
+			}
+		}
+	}
+	return nil
+}
+
+// IsReadOnlyAccessMode: Return true if the access mode only allows reading from the volume
+func IsReadOnlyAccessMode(mode csi.VolumeCapability_AccessMode_Mode) bool {
+	return mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY ||
+		mode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+}
+
+// ValidateFsType: Verify that the filesystem type is one the driver supports
+func ValidateFsType(fsType string) error {
+	for _, supported := range SupportedFsTypes {
+		if fsType == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("filesystem type %q is not supported, must be one of %v", fsType, SupportedFsTypes)
+}
+
+// GetFsLabel converts a persistent volume name into a filesystem label that fits the label length limit of the filesystem
+func GetFsLabel(pvName, fsType string) string {
+	maxLength := 16 // ext2/3/4
+	if fsType == "xfs" {
+		maxLength = 12
+	} else if fsType == "btrfs" {
+		maxLength = 255
+	}
+
+	label := strings.TrimPrefix(pvName, "pvc-")
+	label = strings.ReplaceAll(label, "-", "")
+	if len(label) > maxLength {
+		label = label[:maxLength]
+	}
+	return label
 }
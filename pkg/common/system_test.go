@@ -20,6 +20,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -87,3 +88,44 @@ func TestValidate(t *testing.T) {
 	g.Expect(ValidateName("abc<def")).To(BeFalse())
 	g.Expect(ValidateName("abc\\def")).To(BeFalse())
 }
+
+func TestValidateMountFlags(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ValidateMountFlags(nil)).To(Succeed())
+	g.Expect(ValidateMountFlags([]string{"noatime", "discard"})).To(Succeed())
+	g.Expect(ValidateMountFlags([]string{"noatime,nodiratime", "commit=60"})).To(Succeed())
+
+	g.Expect(ValidateMountFlags([]string{"bind"})).NotTo(Succeed())
+	g.Expect(ValidateMountFlags([]string{"noatime,remount"})).NotTo(Succeed())
+	g.Expect(ValidateMountFlags([]string{"loop=/dev/loop0"})).NotTo(Succeed())
+}
+
+func TestGetFsLabel(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(GetFsLabel("pvc-03c551d9-7e77-43ff-993e-c2308d2f09a1", "ext4")).To(Equal("03c551d97e7743ff"))
+	g.Expect(GetFsLabel("pvc-03c551d9-7e77-43ff-993e-c2308d2f09a1", "xfs")).To(Equal("03c551d97e77"))
+	g.Expect(GetFsLabel("pvc-03c551d9-7e77-43ff-993e-c2308d2f09a1", "btrfs")).To(Equal("03c551d97e7743ff993ec2308d2f09a1"))
+	g.Expect(GetFsLabel("csi_51d97e77", "ext4")).To(Equal("csi_51d97e77"))
+}
+
+func TestValidateFsType(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ValidateFsType("ext4")).To(Succeed())
+	g.Expect(ValidateFsType("xfs")).To(Succeed())
+	g.Expect(ValidateFsType("")).NotTo(Succeed())
+	g.Expect(ValidateFsType("ntfs")).NotTo(Succeed())
+}
+
+func TestNodeId(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(NodeIdGetName("worker-1")).To(Equal("worker-1"))
+	g.Expect(NodeIdGetName("worker-1" + NodeIdSeparator + "5f2c0c3a1b7e9d04")).To(Equal("worker-1"))
+
+	g.Expect(IsLegacyNodeId("10.0.0.1")).To(BeTrue())
+	g.Expect(IsLegacyNodeId("fd00::1")).To(BeTrue())
+	g.Expect(IsLegacyNodeId("worker-1")).To(BeFalse())
+
+	longId := strings.Repeat("worker.", 10)
+	g.Expect(GetTopologyCompliantNodeID(longId)).To(HaveLen(63))
+	g.Expect(GetTopologyCompliantNodeID(longId)).NotTo(Equal(GetTopologyCompliantNodeID(longId + "0")))
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/client"
+	storageapitypes "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
+)
+
+// sessionAPI: Return a client of the array API using the session of the storage client, for the commands the array
+// library does not wrap. It is built again for each request, since the library logs in again when failing over.
+func sessionAPI(storageClient *storageapi.Client) *client.DefaultApiService {
+	configuration := &client.Configuration{
+		DefaultHeader: map[string]string{
+			"datatype":   "json",
+			"sessionKey": storageClient.SessionKey,
+		},
+		UserAgent: "MC OpenAPI",
+		Servers: client.ServerConfigurations{
+			{URL: fmt.Sprintf("%s://%s/api", storageClient.Protocol, storageClient.CurrentAddr)},
+		},
+		OperationServers: map[string]client.ServerConfigurations{},
+	}
+	return client.NewAPIClient(configuration).DefaultApi
+}
+
+// showHostGroups: Return the host groups of the array along with their hosts and initiators
+func showHostGroups(storageClient *storageapi.Client) ([]client.HostGroupResourceInner, error) {
+	response, apiStatus, _, err := storageapi.ExecuteWithFailover(func() (*client.HostGroupObject, *http.Response, error) {
+		return sessionAPI(storageClient).ShowHostGroupsGet(storageClient.Ctx).Execute()
+	}, storageClient)
+	if err = apiError(apiStatus, err); err != nil {
+		return nil, err
+	}
+	return response.GetHostGroup(), nil
+}
+
+// deleteArrayHost: Delete an array host, its initiators are kept
+func deleteArrayHost(storageClient *storageapi.Client, name string) error {
+	_, apiStatus, _, err := storageapi.ExecuteWithFailover(func() (*client.StatusObject, *http.Response, error) {
+		return sessionAPI(storageClient).DeleteHostsNamesGet(storageClient.Ctx, name).Execute()
+	}, storageClient)
+	return apiError(apiStatus, err)
+}
+
+// apiError: Return the error of a request, or of the array response when the command failed
+func apiError(apiStatus *storageapitypes.ResponseStatus, err error) error {
+	if err != nil {
+		return err
+	}
+	if apiStatus != nil && apiStatus.ResponseTypeNumeric != 0 {
+		return fmt.Errorf("%s (return code %d)", strings.TrimSpace(apiStatus.Response), apiStatus.ReturnCode)
+	}
+	return nil
+}
+
+// targetInitiators: Return the initiators of the host or host group a "host.*" or "host-group.*.*" mapping target
+// names, so that the LUNs used by any member are known. Other targets are initiators themselves.
+func targetInitiators(storageClient *storageapi.Client, target string) ([]string, error) {
+	hostGroup, host := "", ""
+	switch {
+	case strings.HasSuffix(target, ".*.*"):
+		hostGroup = strings.TrimSuffix(target, ".*.*")
+	case strings.HasSuffix(target, ".*"):
+		host = strings.TrimSuffix(target, ".*")
+	default:
+		return []string{target}, nil
+	}
+
+	groups, err := showHostGroups(storageClient)
+	if err != nil {
+		return nil, err
+	}
+	initiators := []string{}
+	for _, group := range groups {
+		if hostGroup != "" && (group.GetName() != hostGroup || group.GetDurableId() == storageapi.UngroupedHostsGroupID) {
+			continue
+		}
+		for _, member := range group.GetHost() {
+			if host != "" && (member.GetName() != host || member.GetDurableId() == storageapi.UngroupedInitiatorHostID) {
+				continue
+			}
+			for _, initiator := range member.GetInitiator() {
+				initiators = append(initiators, initiator.GetId())
+			}
+		}
+	}
+	if len(initiators) == 0 {
+		return nil, fmt.Errorf("no initiator found in mapping target %s", target)
+	}
+	return initiators, nil
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
 	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/client"
@@ -56,7 +60,31 @@ type Controller struct {
 
 	client             *storageapi.Client
 	nodeServiceClients map[string]*grpc.ClientConn
-	runPath            string
+	// publish and volume records
+	records RecordStore
+
+	// initiators learned from node topologies or the node service, by node ID and storage protocol
+	nodeInitiators     map[string]nodeInitiatorsEntry
+	nodeLabelsResolver NodeLabelsResolver
+	// last node service addresses resolved, by node ID
+	nodeAddresses       map[string]string
+	nodeAddressResolver NodeAddressResolver
+	// node plugin versions and capabilities, by node service address
+	nodeInfos map[string]nodeInfoEntry
+	// health event streams of the nodes volumes are published to, by node ID
+	healthWatches map[string]context.CancelFunc
+	nodesMutex    sync.Mutex
+
+	// conditions of the published volumes, as reported by the nodes
+	volumeHealth map[volumeHealthKey]*volumeHealth
+	healthMutex  sync.Mutex
+
+	// credentials of the arrays whose inventory is exported, by array name
+	inventoryArrays map[string]inventoryArray
+	// credentials of the array secret configured for the controller, used for the requests without secrets
+	arrayCredentials map[string]string
+	inventoryMutex   sync.Mutex
+	stopInventory    context.CancelFunc
 }
 
 // DriverCtx contains data common to most calls
@@ -70,14 +98,32 @@ type DriverCtx struct {
 func New() *Controller {
 	client := storageapi.NewClient()
 	controller := &Controller{
-		Driver:             common.NewDriver(client.Collector),
+		Driver:             common.NewDriver(client.Collector, NodeHealthMetrics, InventoryMetrics),
 		client:             client,
-		runPath:            fmt.Sprintf("/var/run/%s", common.PluginName),
+		records:            NewRecordStore(fmt.Sprintf("/var/run/%s", common.PluginName)),
 		nodeServiceClients: map[string]*grpc.ClientConn{},
+		nodeInitiators:     map[string]nodeInitiatorsEntry{},
+		nodeAddresses:      map[string]string{},
+		nodeInfos:          map[string]nodeInfoEntry{},
+		healthWatches:      map[string]context.CancelFunc{},
+		volumeHealth:       map[volumeHealthKey]*volumeHealth{},
+		inventoryArrays:    map[string]inventoryArray{},
 	}
-
-	if err := os.MkdirAll(controller.runPath, 0755); err != nil {
-		panic(err)
+	controller.nodeAddressResolver = newNodeAddressResolver()
+	controller.nodeLabelsResolver = newNodeLabelsResolver()
+
+	// the API library sends its requests with the default HTTP client
+	controller.InstrumentHTTPClient(http.DefaultClient, arrayCommand)
+	controller.watchRecordedNodes()
+	if path := os.Getenv(common.ArraySecretPathEnvVar); path != "" {
+		if err := controller.loadArrayCredentials(path); err != nil {
+			klog.ErrorS(err, "unable to load the array secret", "path", path)
+		}
+	}
+	if interval := inventoryInterval(); interval > 0 {
+		var ctx context.Context
+		ctx, controller.stopInventory = context.WithCancel(context.Background())
+		go controller.runInventory(ctx, interval)
 	}
 
 	controller.InitServer(
@@ -145,6 +191,9 @@ func (controller *Controller) ControllerGetCapabilities(ctx context.Context, req
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 	}
+	if volumeHealth, _ := strconv.ParseBool(os.Getenv(common.VolumeHealthEnvVar)); volumeHealth {
+		cl = append(cl, csi.ControllerServiceCapability_RPC_GET_VOLUME, csi.ControllerServiceCapability_RPC_VOLUME_CONDITION)
+	}
 
 	for _, cap := range cl {
 		klog.V(4).Infof("enabled controller service capability: %v", cap.String())
@@ -170,10 +219,13 @@ func (controller *Controller) ValidateVolumeCapabilities(ctx context.Context, re
 	if len(req.GetVolumeCapabilities()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "cannot validate volume without capabilities")
 	}
-	_, _, err := controller.client.ShowVolumes(volumeName)
-	if err != nil {
+	volumes, _, err := controller.client.ShowVolumes(volumeName)
+	if err != nil || len(volumes) == 0 {
 		return nil, status.Error(codes.NotFound, "cannot validate volume not found")
 	}
+	if err := isValidVolumeCapabilities(req.GetVolumeCapabilities()); err != nil {
+		return &csi.ValidateVolumeCapabilitiesResponse{Message: err.Error()}, nil
+	}
 
 	return &csi.ValidateVolumeCapabilitiesResponse{
 		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
@@ -192,9 +244,25 @@ func (controller *Controller) GetCapacity(ctx context.Context, req *csi.GetCapac
 	return nil, status.Error(codes.Unimplemented, "GetCapacity is unimplemented and should not be called")
 }
 
-// ControllerGetVolume fetch current information about a volume
+// ControllerGetVolume returns the nodes a volume is published to and its condition, as reported by these nodes. The
+// request carries no credentials, so the array is not queried.
 func (controller *Controller) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ControllerGetVolume is unimplemented and should not be called")
+	volumeName, _ := common.VolumeIdGetName(req.GetVolumeId())
+	if len(volumeName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot get volume with empty ID")
+	}
+
+	nodeIDs := []string{}
+	for _, record := range listPublishRecords(controller.records, volumeName, "") {
+		nodeIDs = append(nodeIDs, record.NodeId)
+	}
+	return &csi.ControllerGetVolumeResponse{
+		Volume: &csi.Volume{VolumeId: req.GetVolumeId()},
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: nodeIDs,
+			VolumeCondition:  controller.volumeCondition(volumeName),
+		},
+	}, nil
 }
 
 // Probe returns the health and readiness of the plugin
@@ -202,6 +270,15 @@ func (controller *Controller) Probe(ctx context.Context, req *csi.ProbeRequest)
 	return &csi.ProbeResponse{}, nil
 }
 
+// arrayCommand: The array API command of a request without its arguments, "show volumes" for /api/show/volumes/vol1
+func arrayCommand(request *http.Request) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(request.URL.Path, "/api"), "/"), "/")
+	if len(segments) > 2 {
+		segments = segments[:2]
+	}
+	return strings.Join(segments, " ")
+}
+
 func (controller *Controller) beginRoutine(ctx *DriverCtx, methodName string) error {
 	if err := runPreflightChecks(ctx.Parameters, ctx.VolumeCaps); err != nil {
 		return err
@@ -220,7 +297,11 @@ func (controller *Controller) beginRoutine(ctx *DriverCtx, methodName string) er
 	}
 
 	if ctx.Credentials == nil {
-		return errors.New("missing API credentials")
+		if controller.arrayCredentials == nil {
+			return errors.New("missing API credentials")
+		}
+		klog.V(2).InfoS("no secrets in the request, using the configured array secret", "method", methodName)
+		ctx.Credentials = controller.arrayCredentials
 	}
 
 	return controller.configureClient(ctx.Credentials)
@@ -234,7 +315,6 @@ func (controller *Controller) configureClient(credentials map[string]string) err
 	username := string(credentials[common.UsernameSecretKey])
 	password := string(credentials[common.PasswordSecretKey])
 	apiAddr := string(credentials[common.APIAddressConfigKey])
-	secondaryapiAddr := string(credentials[common.APIAddressBConfigKey])
 
 	if len(username) == 0 {
 		return status.Error(codes.InvalidArgument, fmt.Sprintf("(%s) is missing from secrets", common.UsernameSecretKey))
@@ -249,10 +329,7 @@ func (controller *Controller) configureClient(credentials map[string]string) err
 		return status.Error(codes.InvalidArgument, fmt.Sprintf("(%s) is missing from secrets", common.APIAddressConfigKey))
 	}
 
-	apiAddresses := []string{apiAddr}
-	if secondaryapiAddr != "" {
-		apiAddresses = append(apiAddresses, secondaryapiAddr)
-	}
+	apiAddresses := credentialAddresses(credentials)
 	klog.InfoS("using API", "addresses", apiAddresses)
 
 	controller.client.StoreCredentials(apiAddresses, "", username, password)
@@ -267,11 +344,21 @@ func (controller *Controller) configureClient(credentials map[string]string) err
 	}
 
 	klog.Info("login was successful")
+	controller.rememberArray(apiAddresses, username, password)
 	err = controller.client.InitSystemInfo()
 
 	return err
 }
 
+// credentialAddresses: Return the API addresses of the array of a secret, the secondary address is optional
+func credentialAddresses(credentials map[string]string) []string {
+	apiAddresses := []string{credentials[common.APIAddressConfigKey]}
+	if secondaryapiAddr := credentials[common.APIAddressBConfigKey]; secondaryapiAddr != "" {
+		apiAddresses = append(apiAddresses, secondaryapiAddr)
+	}
+	return apiAddresses
+}
+
 func runPreflightChecks(parameters map[string]string, capabilities *[]*csi.VolumeCapability) error {
 	checkIfKeyExistsInConfig := func(key string) error {
 		if parameters == nil {
@@ -319,51 +406,170 @@ func runPreflightChecks(parameters map[string]string, capabilities *[]*csi.Volum
 	return nil
 }
 
+// how long initiators learned from a node are used when its topology cannot be read, as HBAs and IQNs may be replaced
+const nodeInitiatorsTTL = 10 * time.Minute
+
+// nodeInitiatorsEntry: the initiators last learned from a node for a storage protocol
+type nodeInitiatorsEntry struct {
+	initiators []string
+	expires    time.Time
+}
+
+// getNodeInitiators: Return the initiators of a node for a storage protocol. They are read from the live node topology
+// in the node labels, then from the node topology stored in the volume context by CreateVolume, then from a recent
+// previous call, so the node service is only a fallback.
+func (controller *Controller) getNodeInitiators(ctx context.Context, nodeID string, protocol string, volumeContext map[string]string) ([]string, error) {
+	if protocol == "" {
+		protocol = common.StorageProtocolISCSI
+	}
+	key := nodeID + common.AugmentKey + protocol
+	nodeAddress := controller.resolveNodeAddress(ctx, nodeID)
+
+	initiators, complete := []string{}, true
+	if !common.IsLegacyNodeId(nodeID) {
+		labels, err := controller.nodeLabelsResolver(ctx, common.NodeIdGetName(nodeID))
+		if err != nil {
+			klog.V(2).InfoS("unable to read node topology", "nodeID", nodeID, "err", err)
+		}
+		initiators, complete = labelInitiators(nodeID, protocol, labels)
+	}
+	if len(initiators) == 0 && complete {
+		initiators, complete = topologyInitiators(nodeID, protocol, volumeContext)
+	}
+	if len(initiators) == 0 && common.IsLegacyNodeId(nodeAddress) {
+		// volumes created before nodes were identified by name hold initiators under the node IP
+		initiators, complete = topologyInitiators(nodeAddress, protocol, volumeContext)
+	}
+	if !complete {
+		// mapping a subset of the initiators would leave paths of the node without access
+		klog.V(2).InfoS("node topology is missing initiators", "nodeID", nodeID, "protocol", protocol, "published", initiators)
+		initiators = []string{}
+	}
+	if len(initiators) == 0 {
+		controller.nodesMutex.Lock()
+		if entry, ok := controller.nodeInitiators[key]; ok && time.Now().Before(entry.expires) {
+			initiators = entry.initiators
+		}
+		controller.nodesMutex.Unlock()
+	}
+	if len(initiators) == 0 {
+		klog.V(2).InfoS("node initiators not found in topology, requesting them from the node service", "nodeID", nodeID, "nodeAddress", nodeAddress, "protocol", protocol)
+		var err error
+		initiators, err = controller.GetNodeInitiators(ctx, nodeAddress, protocol)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	controller.nodesMutex.Lock()
+	controller.nodeInitiators[key] = nodeInitiatorsEntry{initiators: initiators, expires: time.Now().Add(nodeInitiatorsTTL)}
+	controller.nodesMutex.Unlock()
+	return initiators, nil
+}
+
+// forgetNodeInitiators: Drop the initiators learned from a node, so that they are read again the next time they are
+// needed. Callers hold nodesMutex.
+func (controller *Controller) forgetNodeInitiators(nodeID string) {
+	for _, protocol := range []string{common.StorageProtocolISCSI, common.StorageProtocolFC, common.StorageProtocolSAS} {
+		delete(controller.nodeInitiators, nodeID+common.AugmentKey+protocol)
+	}
+}
+
+// resolveNodeAddress: Return the address of the node service of a node. Legacy node IDs are the node IP, otherwise the
+// address is looked up live, since node addresses may change while volumes stay published. The last address resolved
+// is used while the lookup fails.
+func (controller *Controller) resolveNodeAddress(ctx context.Context, nodeID string) string {
+	if common.IsLegacyNodeId(nodeID) {
+		return nodeID
+	}
+
+	nodeName := common.NodeIdGetName(nodeID)
+	address, err := controller.nodeAddressResolver(ctx, nodeName)
+	controller.nodesMutex.Lock()
+	defer controller.nodesMutex.Unlock()
+	if err != nil || address == "" {
+		klog.V(2).InfoS("unable to resolve node address", "nodeID", nodeID, "lastAddress", controller.nodeAddresses[nodeID], "err", err)
+		return controller.nodeAddresses[nodeID]
+	}
+	controller.nodeAddresses[nodeID] = address
+	return address
+}
+
+// SetNodeAddressResolver: Replace the lookup of node service addresses, for environments without a Kubernetes API
+func (controller *Controller) SetNodeAddressResolver(resolver NodeAddressResolver) {
+	controller.nodeAddressResolver = resolver
+}
+
+// SetNodeLabelsResolver: Replace the lookup of node labels, for environments without a Kubernetes API
+func (controller *Controller) SetNodeLabelsResolver(resolver NodeLabelsResolver) {
+	controller.nodeLabelsResolver = resolver
+}
+
 // Makes an RPC call to the specified node to retrieve initiators of the specified type (iSCSI,FC,SAS)
 // Handles re-use of the relatively expensive grpc Channel(grpc.ClientConn)
 // The gRPC stub is created and destroyed on each call
 func (controller *Controller) GetNodeInitiators(ctx context.Context, nodeAddress string, protocol string) ([]string, error) {
-	var reqType pb.InitiatorType
+	reqType := protocolInitiatorType(protocol)
+	clientConnection, err := controller.nodeServiceClient(nodeAddress)
+	if err != nil {
+		return nil, err
+	}
+	initiators, err := node_service.GetNodeInitiators(ctx, clientConnection, reqType)
+	return initiators, err
+}
+
+// protocolInitiatorType: Return the node service initiator type of a storage protocol
+func protocolInitiatorType(protocol string) pb.InitiatorType {
 	switch protocol {
 	case common.StorageProtocolSAS:
-		reqType = pb.InitiatorType_SAS
+		return pb.InitiatorType_SAS
 	case common.StorageProtocolFC:
-		reqType = pb.InitiatorType_FC
+		return pb.InitiatorType_FC
 	case common.StorageProtocolISCSI:
-		reqType = pb.InitiatorType_ISCSI
+		return pb.InitiatorType_ISCSI
 	}
+	return pb.InitiatorType_UNSPECIFIED
+}
 
-	clientConnection := controller.nodeServiceClients[nodeAddress]
-	if clientConnection == nil {
-		klog.V(3).InfoS("node grpc client not found, establishing...", "nodeAddress", nodeAddress)
-		var err error
-		clientConnection, err = node_service.InitializeClient(nodeAddress)
-		if err != nil {
-			return nil, err
-		}
-		controller.nodeServiceClients[nodeAddress] = clientConnection
+func (controller *Controller) NotifyUnmap(ctx context.Context, nodeAddress string, volumeWWN string) error {
+	clientConnection, err := controller.nodeServiceClient(nodeAddress)
+	if err != nil {
+		return err
 	}
-	initiators, err := node_service.GetNodeInitiators(ctx, clientConnection, reqType)
-	return initiators, err
+	return node_service.NotifyUnmap(ctx, clientConnection, volumeWWN)
 }
 
-func (controller *Controller) NotifyUnmap(ctx context.Context, nodeAddress string, volumeWWN string) error {
+// nodeServiceClient: Return the gRPC channel to the node service of a node, established on first use
+func (controller *Controller) nodeServiceClient(nodeAddress string) (*grpc.ClientConn, error) {
+	if nodeAddress == "" {
+		return nil, status.Error(codes.Unavailable, "node service address unknown")
+	}
+	controller.nodesMutex.Lock()
+	defer controller.nodesMutex.Unlock()
 	clientConnection := controller.nodeServiceClients[nodeAddress]
 	if clientConnection == nil {
 		klog.V(3).InfoS("node grpc client not found, establishing...", "nodeAddress", nodeAddress)
 		var err error
 		clientConnection, err = node_service.InitializeClient(nodeAddress)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		controller.nodeServiceClients[nodeAddress] = clientConnection
 	}
-	return node_service.NotifyUnmap(ctx, clientConnection, volumeWWN)
+	return clientConnection, nil
 }
 
 // Graceful shutdown of Node-Controller RPC Clients
 func (controller *Controller) Stop() {
 	klog.V(3).InfoS("Controller code graceful shutdown..")
+	controller.nodesMutex.Lock()
+	for _, cancel := range controller.healthWatches {
+		cancel()
+	}
+	controller.nodesMutex.Unlock()
+	if controller.stopInventory != nil {
+		controller.stopInventory()
+	}
 	for nodeIP, clientConn := range controller.nodeServiceClients {
 		klog.V(3).InfoS("Closing node client", "nodeIP", nodeIP)
 		clientConn.Close()
@@ -0,0 +1,280 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/simulator"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestController() *Controller {
+	return &Controller{
+		nodeInitiators:     map[string]nodeInitiatorsEntry{},
+		nodeAddresses:      map[string]string{},
+		nodeServiceClients: map[string]*grpc.ClientConn{},
+		nodeInfos:          map[string]nodeInfoEntry{},
+		healthWatches:      map[string]context.CancelFunc{},
+		volumeHealth:       map[volumeHealthKey]*volumeHealth{},
+		inventoryArrays:    map[string]inventoryArray{},
+		nodeAddressResolver: func(ctx context.Context, nodeName string) (string, error) {
+			return "", errors.New("no Kubernetes API")
+		},
+		nodeLabelsResolver: func(ctx context.Context, nodeName string) (map[string]string, error) {
+			return nil, errors.New("no Kubernetes API")
+		},
+	}
+}
+
+// staticNodeAddresses: Resolve node names from a map, as the Kubernetes node objects would
+func staticNodeAddresses(addresses map[string]string) NodeAddressResolver {
+	return func(ctx context.Context, nodeName string) (string, error) {
+		if address, ok := addresses[nodeName]; ok {
+			return address, nil
+		}
+		return "", fmt.Errorf("node %s not found", nodeName)
+	}
+}
+
+func TestResolveNodeAddress(t *testing.T) {
+	g := NewWithT(t)
+	controller := newTestController()
+	ctx := context.Background()
+	addresses := map[string]string{}
+	controller.SetNodeAddressResolver(staticNodeAddresses(addresses))
+
+	g.Expect(controller.resolveNodeAddress(ctx, "10.0.0.1")).To(Equal("10.0.0.1"))
+	g.Expect(controller.resolveNodeAddress(ctx, "worker-1")).To(BeEmpty())
+
+	addresses["worker-1"] = "10.0.0.5"
+	g.Expect(controller.resolveNodeAddress(ctx, "worker-1")).To(Equal("10.0.0.5"))
+	// node IDs issued with a digest of the initiators resolve by their node name
+	g.Expect(controller.resolveNodeAddress(ctx, "worker-1"+common.NodeIdSeparator+"5f2c0c3a1b7e9d04")).To(Equal("10.0.0.5"))
+
+	// the node IP changed
+	addresses["worker-1"] = "10.0.0.6"
+	g.Expect(controller.resolveNodeAddress(ctx, "worker-1")).To(Equal("10.0.0.6"))
+
+	// the last address is used while the node cannot be looked up
+	delete(addresses, "worker-1")
+	g.Expect(controller.resolveNodeAddress(ctx, "worker-1")).To(Equal("10.0.0.6"))
+}
+
+func TestKubernetesNodeAddresses(t *testing.T) {
+	g := NewWithT(t)
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}, Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+			{Type: corev1.NodeHostName, Address: "worker-1"},
+			{Type: corev1.NodeExternalIP, Address: "203.0.113.5"},
+			{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+		}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}, Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+			{Type: corev1.NodeExternalIP, Address: "203.0.113.6"},
+		}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-3"}},
+	)
+	resolve := kubernetesNodeAddresses(client)
+	ctx := context.Background()
+
+	g.Expect(resolve(ctx, "worker-1")).To(Equal("10.0.0.5"))
+	g.Expect(resolve(ctx, "worker-2")).To(Equal("203.0.113.6"))
+	_, err := resolve(ctx, "worker-3")
+	g.Expect(err).To(MatchError(ContainSubstring("no IP address")))
+	_, err = resolve(ctx, "worker-4")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestArrayCommand(t *testing.T) {
+	g := NewWithT(t)
+	for path, command := range map[string]string{
+		"/api/login":             "login",
+		"/api/show/volumes/vol1": "show volumes",
+		"/api/show/pools":        "show pools",
+		"/api/map/volume/access/rw/lun/1/initiator/x": "map volume",
+	} {
+		request, err := http.NewRequest(http.MethodGet, "https://10.0.0.1"+path, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(arrayCommand(request)).To(Equal(command))
+	}
+}
+
+func TestGetNodeInitiatorsLegacyVolume(t *testing.T) {
+	g := NewWithT(t)
+	controller := newTestController()
+	nodeID := "worker-1"
+	controller.SetNodeAddressResolver(staticNodeAddresses(map[string]string{nodeID: "10.0.0.5"}))
+
+	// a volume created while the node was identified by its IP, published after the node was upgraded
+	volumeContext := map[string]string{"10.0.0.5/sas-address-0": "500605b00db5e3a0"}
+	initiators, err := controller.getNodeInitiators(context.Background(), nodeID, common.StorageProtocolSAS, volumeContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(initiators).To(Equal([]string{"500605b00db5e3a0"}))
+
+	// unpublish has no volume context and relies on what was learned when publishing
+	initiators, err = controller.getNodeInitiators(context.Background(), nodeID, common.StorageProtocolSAS, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(initiators).To(Equal([]string{"500605b00db5e3a0"}))
+}
+
+func TestGetNodeInitiatorsIncompleteTopology(t *testing.T) {
+	g := NewWithT(t)
+	controller := newTestController()
+	nodeID := "worker-1"
+	initiators := []string{"500605b00db5e3a0", "500605b00db5e3a1"}
+	controller.nodeInitiators[nodeID+common.AugmentKey+common.StorageProtocolSAS] = nodeInitiatorsEntry{initiators: initiators, expires: time.Now().Add(time.Minute)}
+
+	// the node could only publish one of its two initiators, the complete list is used instead
+	volumeContext := map[string]string{
+		nodeID + "/sas-address-0": "500605b00db5e3a0",
+		nodeID + "/" + common.GetTopologyInitiatorCountLabel(common.StorageProtocolSAS): "2",
+	}
+	g.Expect(controller.getNodeInitiators(context.Background(), nodeID, common.StorageProtocolSAS, volumeContext)).To(Equal(initiators))
+}
+
+func TestGetNodeInitiatorsFromLabels(t *testing.T) {
+	g := NewWithT(t)
+	controller := newTestController()
+	nodeID := "worker-1"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeID, Labels: map[string]string{
+		common.TopologyNodeIDKey:                                   nodeID,
+		common.GetTopologyProtocolKey(common.StorageProtocolISCSI): "true",
+		common.TopologyInitiatorPrefix + "/" + common.GetTopologyInitiatorCountLabel(common.StorageProtocolISCSI): "1",
+		common.TopologyInitiatorPrefix + "/iscsi-initiator-0":                                                     "iqn.1993-08.org.debian_01_node2",
+	}}}
+	client := fake.NewSimpleClientset(node)
+	controller.SetNodeLabelsResolver(kubernetesNodeLabels(client))
+	ctx := context.Background()
+
+	// the node IQN changed since the volume was created, the node labels win over the volume context
+	volumeContext := map[string]string{nodeID + "/iscsi-initiator-0": "iqn.1993-08.org.debian:01:node1"}
+	g.Expect(controller.getNodeInitiators(ctx, nodeID, common.StorageProtocolISCSI, volumeContext)).To(Equal([]string{"iqn.1993-08.org.debian:01:node2"}))
+
+	// the initiators learned are used while the labels cannot be read, until they expire
+	g.Expect(client.CoreV1().Nodes().Delete(ctx, nodeID, metav1.DeleteOptions{})).To(Succeed())
+	g.Expect(controller.getNodeInitiators(ctx, nodeID, common.StorageProtocolISCSI, nil)).To(Equal([]string{"iqn.1993-08.org.debian:01:node2"}))
+
+	key := nodeID + common.AugmentKey + common.StorageProtocolISCSI
+	entry := controller.nodeInitiators[key]
+	entry.expires = time.Now().Add(-time.Second)
+	controller.nodeInitiators[key] = entry
+	_, err := controller.getNodeInitiators(ctx, nodeID, common.StorageProtocolISCSI, nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestCheckLUNSpace(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	controller := newTestController()
+	controller.client = storageapi.NewClient()
+	controller.client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(controller.client.Login(context.Background())).To(Succeed())
+
+	initiator := "iqn.1993-08.org.debian:01:node1"
+	g.Expect(controller.checkLUNSpace("new", []string{initiator})).To(Succeed())
+
+	// LUNs 0 to 253 are taken, as if mapped outside of the driver
+	mapLUN := func(lun int) {
+		name := fmt.Sprintf("vol%d", lun)
+		_, _, err := controller.client.CreateVolume(name, "1GiB", simulator.DefaultPool)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = controller.client.MapVolume(name, initiator, "rw", lun)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	for lun := 0; lun < common.MaximumLUN-1; lun++ {
+		mapLUN(lun)
+	}
+	// LUN 0 is never used by the driver, so LUN 254 is still available
+	g.Expect(controller.checkLUNSpace("new", []string{initiator})).To(Succeed())
+
+	mapLUN(common.MaximumLUN - 1)
+	err := controller.checkLUNSpace("new", []string{initiator})
+	g.Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+	g.Expect(controller.checkLUNSpace("vol1", []string{initiator})).To(Succeed())
+}
+
+func TestUnpublishWithoutNode(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+	// nothing listens on the node service port, as if the node was gone
+	t.Setenv(common.NodeServicePortEnvVar, "1")
+
+	controller := newTestController()
+	controller.records = newFileRecordStore(t.TempDir())
+	controller.client = storageapi.NewClient()
+	controller.client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(controller.client.Login(context.Background())).To(Succeed())
+
+	initiator := "iqn.1993-08.org.debian:01:node1"
+	nodeID := "worker-1"
+	volume, _, err := controller.client.CreateVolume("vol1", "1GiB", simulator.DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+	volumeID := common.VolumeIdAugment("vol1", common.StorageProtocolISCSI, volume.Wwn)
+
+	_, err = controller.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId:         volumeID,
+		NodeId:           nodeID,
+		VolumeCapability: volumeCapabilities[0],
+		VolumeContext: map[string]string{
+			common.StorageProtocolKey: common.StorageProtocolISCSI,
+			common.GetTopologyCompliantNodeID(nodeID) + "/" + common.GetTopologyInitiatorLabel(common.StorageProtocolISCSI) + "-0": initiator,
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// a failed unmapping keeps the record so that unpublishing is retried
+	g.Expect(controller.client.Logout()).To(Succeed())
+	_, err = controller.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{VolumeId: volumeID, NodeId: nodeID})
+	g.Expect(status.Code(err)).To(Equal(codes.Internal))
+	g.Expect(loadPublishRecord(controller.records, "vol1", nodeID)).NotTo(BeNil())
+	g.Expect(controller.client.Login(context.Background())).To(Succeed())
+
+	// a restarted controller has lost its node caches but not the publish records
+	restarted := newTestController()
+	restarted.records = controller.records
+	restarted.client = controller.client
+	_, err = restarted.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{VolumeId: volumeID, NodeId: nodeID})
+	g.Expect(err).NotTo(HaveOccurred())
+	volumes, _, err := controller.client.ShowHostMaps(initiator)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(volumes).To(BeEmpty())
+	g.Expect(loadPublishRecord(controller.records, "vol1", nodeID)).To(BeNil())
+
+	// without a record the initiators must come from the node
+	_, err = restarted.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{VolumeId: volumeID, NodeId: "127.0.0.1"})
+	g.Expect(status.Code(err)).To(Equal(codes.Unavailable))
+}
@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"k8s.io/klog/v2"
+)
+
+// Fence action results
+const (
+	FenceResultDone    = "done"
+	FenceResultDryRun  = "dry-run"
+	FenceResultFailed  = "failed"
+	FenceResultSkipped = "skipped"
+)
+
+// FenceOptions: which node to fence and how
+type FenceOptions struct {
+	NodeID     string
+	Initiators []string // in addition to the initiators recorded when publishing volumes to the node
+	DeleteHost bool     // delete the array host holding the initiators once its volumes are unmapped
+	DryRun     bool
+}
+
+// FenceAction: an audit entry of what fencing did or would do on the array
+type FenceAction struct {
+	Action  string `json:"action"`
+	Volume  string `json:"volume,omitempty"`
+	Target  string `json:"target"`
+	LUN     int    `json:"lun,omitempty"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// FenceNode: Revoke the access of a failed node to the array by removing every mapping of its initiators, whether the
+// volumes were published by the driver or not. The audit function is called for each action as it is taken. Volumes
+// mapped through a host group are reported as failed, since unmapping them would revoke the access of the whole group.
+// Publish records are kept so that unpublishing the volumes from the node, once Kubernetes detaches them, succeeds.
+func FenceNode(storageClient *storageapi.Client, records RecordStore, options FenceOptions, audit func(FenceAction)) ([]FenceAction, error) {
+	initiators := recordedInitiators(records, options.NodeID, options.Initiators)
+	if len(initiators) == 0 {
+		return nil, fmt.Errorf("no initiators known for node %s, specify them explicitly", options.NodeID)
+	}
+	klog.InfoS("fencing node", "nodeID", options.NodeID, "initiators", initiators, "deleteHost", options.DeleteHost, "dryRun", options.DryRun)
+
+	actions := []FenceAction{}
+	record := func(action FenceAction) {
+		actions = append(actions, action)
+		if audit != nil {
+			audit(action)
+		}
+	}
+
+	hostGroup, host := "", ""
+	for _, initiator := range initiators {
+		var err error
+		if hostGroup, host, err = storageClient.GetInitiatorHostGroup(initiator); err == nil {
+			break
+		}
+	}
+
+	failed := false
+	for _, initiator := range initiators {
+		volumes, _, err := storageClient.ShowHostMaps(initiator)
+		if err != nil {
+			// mappings which could not be listed may remain
+			klog.ErrorS(err, "error looking for host maps", "initiator", initiator)
+			failed = true
+			record(FenceAction{Action: "list-mappings", Target: initiator, Result: FenceResultFailed, Message: err.Error()})
+			continue
+		}
+		for _, volume := range volumes {
+			action := FenceAction{Action: "unmap", Volume: volume.Name, Target: initiator, LUN: volume.LUN, Result: FenceResultDryRun}
+			if !options.DryRun {
+				action = fenceUnmap(storageClient, action, host, hostGroup)
+			}
+			failed = failed || action.Result == FenceResultFailed
+			record(action)
+		}
+	}
+
+	if options.DeleteHost {
+		action := FenceAction{Action: "delete-host", Target: host, Result: FenceResultDryRun}
+		switch {
+		case host == "":
+			action.Result, action.Message = FenceResultSkipped, "initiators are not part of a host"
+		case failed:
+			action.Result, action.Message = FenceResultSkipped, "mappings of the initiators remain or could not be listed"
+		case !options.DryRun:
+			action = deleteHost(storageClient, action)
+		}
+		record(action)
+	}
+	return actions, nil
+}
+
+// recordedInitiators: Return the given initiators along with the initiators recorded when publishing volumes to a node
+func recordedInitiators(records RecordStore, nodeID string, initiators []string) []string {
+	known := map[string]bool{}
+	for _, initiator := range initiators {
+		known[initiator] = true
+	}
+	for _, record := range listPublishRecords(records, "", nodeID) {
+		for _, initiator := range record.Initiators {
+			known[initiator] = true
+		}
+	}
+
+	result := []string{}
+	for initiator := range known {
+		result = append(result, initiator)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// fenceUnmap: Unmap a volume from an initiator, or from the host holding it when the volume is mapped to the host
+func fenceUnmap(storageClient *storageapi.Client, action FenceAction, host string, hostGroup string) FenceAction {
+	targets := []string{action.Target}
+	if host != "" {
+		targets = append(targets, host+".*")
+	}
+	for _, target := range targets {
+		apiStatus, err := storageClient.UnmapVolume(action.Volume, target)
+		if err == nil && apiStatus.ResponseTypeNumeric == 0 {
+			action.Target, action.Result = target, FenceResultDone
+			return action
+		}
+	}
+	action.Result = FenceResultFailed
+	action.Message = "the volume could not be unmapped from the initiator or its host"
+	if hostGroup != "" {
+		action.Message = fmt.Sprintf("the volume is mapped to host group %s, remove the host from the group to revoke its access", hostGroup)
+	}
+	return action
+}
+
+// deleteHost: Delete an array host, its initiators are kept
+func deleteHost(storageClient *storageapi.Client, action FenceAction) FenceAction {
+	if err := deleteArrayHost(storageClient, action.Target); err != nil {
+		action.Result, action.Message = FenceResultFailed, err.Error()
+		return action
+	}
+	action.Result = FenceResultDone
+	return action
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/simulator"
+	. "github.com/onsi/gomega"
+)
+
+func TestFenceNode(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	client := storageapi.NewClient()
+	client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(client.Login(context.Background())).To(Succeed())
+
+	node1 := "iqn.1993-08.org.debian:01:node1"
+	node2 := "iqn.1993-08.org.debian:01:node2"
+	nodeID := "worker-1"
+	sim.AddHost("worker-1", "", node1)
+	for _, name := range []string{"vol1", "vol2", "vol3"} {
+		_, _, err := client.CreateVolume(name, "1GiB", simulator.DefaultPool)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	// vol1 was published by the driver, vol2 mapped to the host by hand and vol3 to another node
+	_, err := client.MapVolume("vol1", node1, "rw", 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = client.MapVolume("vol2", "worker-1.*", "rw", 2)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = client.MapVolume("vol3", node2, "rw", 1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	records := newFileRecordStore(t.TempDir())
+	record := &publishRecord{VolumeId: "vol1", NodeId: nodeID, Initiators: []string{node1}, Targets: []string{node1}}
+	g.Expect(record.save(records)).To(Succeed())
+
+	_, err = FenceNode(client, records, FenceOptions{NodeID: "worker-2"}, nil)
+	g.Expect(err).To(HaveOccurred())
+
+	actions, err := FenceNode(client, records, FenceOptions{NodeID: nodeID, DeleteHost: true, DryRun: true}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions).To(HaveLen(3))
+	g.Expect(sim.Mappings("vol1")).To(HaveKey(node1))
+
+	// mappings which cannot be listed fail fencing and keep the host
+	unknown := "iqn.1993-08.org.debian:01:unknown"
+	actions, err = FenceNode(client, records, FenceOptions{NodeID: nodeID, Initiators: []string{unknown}, DeleteHost: true, DryRun: true}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions).To(ContainElement(And(HaveField("Action", "list-mappings"), HaveField("Target", unknown), HaveField("Result", FenceResultFailed))))
+	g.Expect(actions[len(actions)-1]).To(Equal(FenceAction{Action: "delete-host", Target: "worker-1", Result: FenceResultSkipped,
+		Message: "mappings of the initiators remain or could not be listed"}))
+
+	audited := []FenceAction{}
+	actions, err = FenceNode(client, records, FenceOptions{NodeID: nodeID, DeleteHost: true}, func(action FenceAction) {
+		audited = append(audited, action)
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(audited).To(Equal(actions))
+	g.Expect(actions).To(Equal([]FenceAction{
+		{Action: "unmap", Volume: "vol1", Target: node1, LUN: 1, Result: FenceResultDone},
+		{Action: "unmap", Volume: "vol2", Target: node1, LUN: 2, Result: FenceResultDone},
+		{Action: "delete-host", Target: "worker-1", Result: FenceResultDone},
+	}))
+	g.Expect(sim.Mappings("vol1")).To(BeEmpty())
+	g.Expect(sim.Mappings("vol2")).To(BeEmpty())
+	g.Expect(sim.Mappings("vol3")).To(HaveKey(node2))
+	_, host, err := client.GetInitiatorHostGroup(node1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(host).To(BeEmpty())
+	g.Expect(loadPublishRecord(records, "vol1", nodeID)).NotTo(BeNil())
+}
@@ -0,0 +1,246 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/exporter"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/node_service"
+	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"k8s.io/klog/v2"
+)
+
+// NodeHealthMetrics exports the device health events received from the nodes, it is registered with the controller exporter
+var NodeHealthMetrics = exporter.NewNodeHealthCollector()
+
+const (
+	// how long to wait before watching the health events of a node again after its stream broke
+	healthRetryDelay = 30 * time.Second
+	// how long a device error keeps the condition of a volume abnormal
+	healthErrorWindow = 10 * time.Minute
+)
+
+type volumeHealthKey struct {
+	volumeName string
+	nodeID     string
+}
+
+// volumeHealth: the condition of a volume on a node, built from the health events of the node
+type volumeHealth struct {
+	failedPaths   map[string]string
+	readOnly      string
+	lastError     string
+	lastErrorTime time.Time
+}
+
+// condition: Return whether the volume is in an abnormal condition on the node, and why
+func (health *volumeHealth) condition(now time.Time) (bool, string) {
+	problems := []string{}
+	if len(health.failedPaths) > 0 {
+		paths := []string{}
+		for path := range health.failedPaths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		problems = append(problems, fmt.Sprintf("%d failed paths (%s)", len(paths), strings.Join(paths, ", ")))
+	}
+	if health.readOnly != "" {
+		problems = append(problems, "filesystem shut down or remounted read-only: "+health.readOnly)
+	}
+	if health.lastError != "" && now.Sub(health.lastErrorTime) < healthErrorWindow {
+		problems = append(problems, "device error: "+health.lastError)
+	}
+	return len(problems) > 0, strings.Join(problems, "; ")
+}
+
+// watchNodeHealth: Receive the device health events of a node until no volume is published to it anymore
+func (controller *Controller) watchNodeHealth(nodeID string) {
+	controller.nodesMutex.Lock()
+	defer controller.nodesMutex.Unlock()
+	if _, watching := controller.healthWatches[nodeID]; watching {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	controller.healthWatches[nodeID] = cancel
+	go controller.runHealthWatch(ctx, nodeID)
+	go controller.refreshNodeHealth(ctx, nodeID)
+}
+
+// runHealthWatch: Receive the health events of a node, resolving its address again each time the stream breaks
+func (controller *Controller) runHealthWatch(ctx context.Context, nodeID string) {
+	for {
+		nodeAddress := controller.resolveNodeAddress(ctx, nodeID)
+		info, err := controller.getNodeInfo(ctx, nodeAddress)
+		if err == nil && !nodeSupports(info, "WatchHealth") {
+			klog.InfoS("node does not report device health events", "nodeID", nodeID, "version", info.GetVersion())
+			controller.stopNodeHealth(nodeID)
+			return
+		}
+		if err == nil {
+			err = controller.receiveHealthEvents(ctx, nodeID, nodeAddress)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		klog.V(2).InfoS("node health events unavailable, retrying", "nodeID", nodeID, "nodeAddress", nodeAddress, "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(healthRetryDelay):
+		}
+	}
+}
+
+func (controller *Controller) receiveHealthEvents(ctx context.Context, nodeID string, nodeAddress string) error {
+	clientConnection, err := controller.nodeServiceClient(nodeAddress)
+	if err != nil {
+		return err
+	}
+	klog.InfoS("watching node health events", "nodeID", nodeID, "nodeAddress", nodeAddress)
+	return node_service.WatchHealth(ctx, clientConnection, nil, func(event *pb.HealthEvent) {
+		controller.recordHealthEvent(nodeID, event)
+	})
+}
+
+// refreshNodeHealth: Update the exported conditions of the volumes of a node as device errors age out
+func (controller *Controller) refreshNodeHealth(ctx context.Context, nodeID string) {
+	ticker := time.NewTicker(healthErrorWindow / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		controller.healthMutex.Lock()
+		for key, health := range controller.volumeHealth {
+			if key.nodeID == nodeID {
+				abnormal, _ := health.condition(time.Now())
+				NodeHealthMetrics.SetVolumeHealth(key.volumeName, key.nodeID, abnormal, len(health.failedPaths))
+			}
+		}
+		controller.healthMutex.Unlock()
+	}
+}
+
+// stopNodeHealth: Stop watching the health events of a node
+func (controller *Controller) stopNodeHealth(nodeID string) {
+	controller.nodesMutex.Lock()
+	defer controller.nodesMutex.Unlock()
+	if cancel, watching := controller.healthWatches[nodeID]; watching {
+		cancel()
+		delete(controller.healthWatches, nodeID)
+	}
+}
+
+// watchRecordedNodes: Resume watching the health events of the nodes volumes were published to before a restart
+func (controller *Controller) watchRecordedNodes() {
+	for _, record := range listPublishRecords(controller.records, "", "") {
+		controller.watchNodeHealth(record.NodeId)
+	}
+}
+
+// recordHealthEvent: Count a health event of a node and update the condition of the volume it is about. Events of
+// devices which are not volumes published to the node are ignored.
+func (controller *Controller) recordHealthEvent(nodeID string, event *pb.HealthEvent) {
+	volumeName := ""
+	for _, record := range listPublishRecords(controller.records, "", nodeID) {
+		if wwn, _ := common.VolumeIdGetWwn(record.VolumeId); strings.EqualFold(wwn, event.GetWwn()) {
+			volumeName, _ = common.VolumeIdGetName(record.VolumeId)
+			break
+		}
+	}
+	if volumeName == "" {
+		klog.V(4).InfoS("ignoring health event of an unknown device", "nodeID", nodeID, "wwn", event.GetWwn(), "type", event.GetType())
+		return
+	}
+	if event.GetType() != pb.HealthEventType_PATH_SNAPSHOT {
+		klog.InfoS("node health event", "volume", volumeName, "nodeID", nodeID, "type", event.GetType(), "severity", event.GetSeverity(),
+			"path", event.GetPath(), "message", event.GetMessage(), "timestamp", time.Unix(0, event.GetTimestamp()))
+		NodeHealthMetrics.IncHealthEvent(volumeName, nodeID, event.GetType().String(), event.GetSeverity().String())
+	}
+
+	controller.healthMutex.Lock()
+	defer controller.healthMutex.Unlock()
+	key := volumeHealthKey{volumeName: volumeName, nodeID: nodeID}
+	health := controller.volumeHealth[key]
+	if health == nil {
+		health = &volumeHealth{failedPaths: map[string]string{}}
+		controller.volumeHealth[key] = health
+	}
+	switch event.GetType() {
+	case pb.HealthEventType_PATH_SNAPSHOT:
+		// the node sends the current failed paths of its devices when the stream starts, replacing what was learned
+		// from the events of a previous stream or before a restart
+		health.failedPaths = map[string]string{}
+		for _, path := range event.GetFailedPaths() {
+			health.failedPaths[path] = "path " + path + " failed"
+		}
+	case pb.HealthEventType_PATH_FAILED:
+		health.failedPaths[event.GetPath()] = event.GetMessage()
+	case pb.HealthEventType_PATH_RESTORED:
+		delete(health.failedPaths, event.GetPath())
+	case pb.HealthEventType_READ_ONLY_REMOUNT:
+		health.readOnly = event.GetMessage()
+	default:
+		health.lastError = event.GetMessage()
+		health.lastErrorTime = time.Unix(0, event.GetTimestamp())
+	}
+	abnormal, _ := health.condition(time.Now())
+	NodeHealthMetrics.SetVolumeHealth(volumeName, nodeID, abnormal, len(health.failedPaths))
+}
+
+// forgetVolumeHealth: Drop the condition of a volume unpublished from a node, and stop watching the node once no volume
+// is published to it anymore
+func (controller *Controller) forgetVolumeHealth(volumeName string, nodeID string) {
+	controller.healthMutex.Lock()
+	delete(controller.volumeHealth, volumeHealthKey{volumeName: volumeName, nodeID: nodeID})
+	controller.healthMutex.Unlock()
+	NodeHealthMetrics.DeleteVolume(volumeName, nodeID)
+	if len(listPublishRecords(controller.records, "", nodeID)) == 0 {
+		controller.stopNodeHealth(nodeID)
+	}
+}
+
+// volumeCondition: Return the condition of a volume across the nodes it is published to
+func (controller *Controller) volumeCondition(volumeName string) *csi.VolumeCondition {
+	controller.healthMutex.Lock()
+	defer controller.healthMutex.Unlock()
+	messages := []string{}
+	for key, health := range controller.volumeHealth {
+		if key.volumeName != volumeName {
+			continue
+		}
+		if abnormal, message := health.condition(time.Now()); abnormal {
+			messages = append(messages, fmt.Sprintf("node %s: %s", key.nodeID, message))
+		}
+	}
+	if len(messages) == 0 {
+		return &csi.VolumeCondition{Abnormal: false, Message: "no problem reported by the nodes"}
+	}
+	sort.Strings(messages)
+	return &csi.VolumeCondition{Abnormal: true, Message: strings.Join(messages, "; ")}
+}
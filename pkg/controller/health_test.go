@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	. "github.com/onsi/gomega"
+)
+
+func TestNodeHealth(t *testing.T) {
+	g := NewWithT(t)
+	service := &testNodeService{
+		info:   &pb.NodeInfo{ApiVersion: common.NodeServiceAPIVersion, Rpcs: []string{"GetNodeInfo", "WatchHealth"}},
+		health: make(chan *pb.HealthEvent),
+	}
+	startTestNodeService(t, service)
+
+	controller := newTestController()
+	controller.records = newFileRecordStore(t.TempDir())
+	nodeID := "127.0.0.1"
+	volumeID := common.VolumeIdAugment("csi_volume", common.StorageProtocolISCSI, "600c0ff0000000000000000000000001")
+	record := &publishRecord{VolumeId: volumeID, NodeId: nodeID}
+	g.Expect(record.save(controller.records)).To(Succeed())
+
+	controller.watchNodeHealth(nodeID)
+	defer controller.stopNodeHealth(nodeID)
+	condition := func() *csi.VolumeCondition {
+		response, err := controller.ControllerGetVolume(context.Background(), &csi.ControllerGetVolumeRequest{VolumeId: volumeID})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(response.GetStatus().GetPublishedNodeIds()).To(Equal([]string{nodeID}))
+		return response.GetStatus().GetVolumeCondition()
+	}
+	g.Expect(condition().GetAbnormal()).To(BeFalse())
+
+	// events of devices which are not published volumes are ignored
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_SCSI_ERROR, Wwn: "600c0ff0000000000000000000000002", Path: "sdd"}
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_PATH_FAILED, Severity: pb.Severity_SEVERITY_WARNING,
+		Wwn: "600C0FF0000000000000000000000001", Path: "sdc", Message: "path sdc failed, 1 valid paths remaining"}
+	g.Eventually(condition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Abnormal", BeTrue()))
+	g.Expect(condition().GetMessage()).To(Equal("node 127.0.0.1: 1 failed paths (sdc)"))
+
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_PATH_RESTORED, Wwn: "600c0ff0000000000000000000000001", Path: "sdc"}
+	g.Eventually(condition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Abnormal", BeFalse()))
+
+	// a snapshot sent when a stream starts replaces the failed paths known from earlier events
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_PATH_SNAPSHOT, Wwn: "600c0ff0000000000000000000000001",
+		FailedPaths: []string{"sdd", "sde"}}
+	g.Eventually(condition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Abnormal", BeTrue()))
+	g.Expect(condition().GetMessage()).To(Equal("node 127.0.0.1: 2 failed paths (sdd, sde)"))
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_PATH_SNAPSHOT, Wwn: "600c0ff0000000000000000000000001"}
+	g.Eventually(condition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Abnormal", BeFalse()))
+
+	// device errors age out
+	service.health <- &pb.HealthEvent{Type: pb.HealthEventType_SCSI_ERROR, Wwn: "600c0ff0000000000000000000000001", Path: "sdc",
+		Message: "I/O error, dev sdc, sector 2048", Timestamp: time.Now().Add(-healthErrorWindow / 2).UnixNano()}
+	g.Eventually(condition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Abnormal", BeTrue()))
+	controller.healthMutex.Lock()
+	abnormal, _ := controller.volumeHealth[volumeHealthKey{volumeName: "csi_volume", nodeID: nodeID}].condition(time.Now().Add(healthErrorWindow))
+	controller.healthMutex.Unlock()
+	g.Expect(abnormal).To(BeFalse())
+
+	// the node is no longer watched once its last volume is unpublished
+	removePublishRecord(controller.records, "csi_volume", nodeID)
+	controller.forgetVolumeHealth("csi_volume", nodeID)
+	g.Expect(controller.healthWatches).To(BeEmpty())
+	g.Expect(controller.volumeCondition("csi_volume").GetAbnormal()).To(BeFalse())
+}
@@ -0,0 +1,383 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/client"
+	storageapitypes "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/exporter"
+	"k8s.io/klog/v2"
+)
+
+// InventoryMetrics holds the last inventory read from each array, for the exporter
+var InventoryMetrics = exporter.NewInventoryCollector()
+
+const defaultInventoryInterval = time.Minute
+
+// array health numeric values, used when an object only reports its health as a string
+var healthValues = map[string]int64{"OK": 0, "Degraded": 1, "Fault": 2, "Unknown": 3, "N/A": 4}
+
+// inventoryArray: the credentials of an array configured or learned from the requests, used to read its inventory in a session of
+// its own so that the scrapes never share the session of the request being served
+type inventoryArray struct {
+	addresses []string
+	username  string
+	password  string
+}
+
+// volumeRecord: the persistent volume a volume was created for, the array only knows the translated name
+type volumeRecord struct {
+	VolumeId string `json:"volumeId"`
+	PVName   string `json:"pvName"`
+	Array    string `json:"array"`
+}
+
+// volumeRecordKey: Return the key of the record of a volume
+func volumeRecordKey(volumeName string) string {
+	return fmt.Sprintf("volume-%s.json", volumeName)
+}
+
+// save: Store the record as JSON
+func (record *volumeRecord) save(records RecordStore) error {
+	volumeName, _ := common.VolumeIdGetName(record.VolumeId)
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return records.save(volumeRecordKey(volumeName), data)
+}
+
+// saveVolumeRecord: Record the persistent volume of a volume, unless it is already recorded
+func saveVolumeRecord(records RecordStore, volumeID string, pvName string, apiAddress string) {
+	volumeName, _ := common.VolumeIdGetName(volumeID)
+	if data, err := records.load(volumeRecordKey(volumeName)); err == nil && data != nil {
+		return
+	}
+	record := &volumeRecord{VolumeId: volumeID, PVName: pvName, Array: arrayName(apiAddress)}
+	if err := record.save(records); err != nil {
+		klog.ErrorS(err, "error saving volume record", "volume", volumeName)
+	}
+}
+
+// removeVolumeRecord: Delete the record of a volume once it is deleted
+func removeVolumeRecord(records RecordStore, volumeName string) {
+	if err := records.remove(volumeRecordKey(volumeName)); err != nil {
+		klog.ErrorS(err, "error removing volume record", "volume", volumeName)
+	}
+}
+
+// listVolumeRecords: Return the records of the volumes of an array
+func listVolumeRecords(records RecordStore, array string) []*volumeRecord {
+	found := records.list("volume-")
+	result := []*volumeRecord{}
+	for _, key := range sortedKeys(found) {
+		record := &volumeRecord{}
+		if err := json.Unmarshal(found[key], record); err != nil {
+			klog.ErrorS(err, "ignoring volume record", "key", key)
+			continue
+		}
+		if record.Array == array {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// arrayName: The array label of the metrics, the address of its first controller without the protocol
+func arrayName(apiAddress string) string {
+	if _, address, found := strings.Cut(apiAddress, "://"); found {
+		return address
+	}
+	return apiAddress
+}
+
+// inventoryInterval: Return how often the arrays are scraped, 0 when scraping is disabled
+func inventoryInterval() time.Duration {
+	value := os.Getenv(common.InventoryIntervalEnvVar)
+	if value == "" {
+		return defaultInventoryInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		klog.ErrorS(err, "invalid inventory interval, using the default", "value", value, "default", defaultInventoryInterval)
+		return defaultInventoryInterval
+	}
+	return interval
+}
+
+// loadArraySecret: Read the address and credentials of an array from a mounted secret, one file per key as in the
+// secrets of the storage classes
+func loadArraySecret(path string) (map[string]string, error) {
+	credentials := map[string]string{}
+	for _, key := range []string{common.APIAddressConfigKey, common.APIAddressBConfigKey, common.UsernameSecretKey, common.PasswordSecretKey} {
+		value, err := os.ReadFile(filepath.Join(path, key))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		credentials[key] = strings.TrimSpace(string(value))
+	}
+	for _, key := range []string{common.APIAddressConfigKey, common.UsernameSecretKey, common.PasswordSecretKey} {
+		if credentials[key] == "" {
+			return nil, fmt.Errorf("(%s) is missing from the array secret %s", key, path)
+		}
+	}
+	return credentials, nil
+}
+
+// loadArrayCredentials: Scrape the inventory of the array of the configured secret from startup rather than from the
+// first request, and use its credentials for the requests which carry no secrets
+func (controller *Controller) loadArrayCredentials(path string) error {
+	credentials, err := loadArraySecret(path)
+	if err != nil {
+		return err
+	}
+	controller.arrayCredentials = credentials
+	controller.rememberArray(credentialAddresses(credentials), credentials[common.UsernameSecretKey], credentials[common.PasswordSecretKey])
+	return nil
+}
+
+// rememberArray: Keep the credentials of an array the controller logged in to, so that its inventory is scraped
+func (controller *Controller) rememberArray(addresses []string, username string, password string) {
+	controller.inventoryMutex.Lock()
+	defer controller.inventoryMutex.Unlock()
+	name := arrayName(addresses[0])
+	if _, known := controller.inventoryArrays[name]; !known {
+		klog.InfoS("scraping array inventory", "array", name)
+	}
+	controller.inventoryArrays[name] = inventoryArray{addresses: addresses, username: username, password: password}
+}
+
+// runInventory: Scrape the inventory of the known arrays from startup, then at every interval until the context is
+// cancelled
+func (controller *Controller) runInventory(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		controller.scrapeArrays(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scrapeArrays: Scrape the inventory of every known array, each scrape taking at most the interval
+func (controller *Controller) scrapeArrays(ctx context.Context, interval time.Duration) {
+	controller.inventoryMutex.Lock()
+	arrays := make(map[string]inventoryArray, len(controller.inventoryArrays))
+	for name, array := range controller.inventoryArrays {
+		arrays[name] = array
+	}
+	controller.inventoryMutex.Unlock()
+
+	for name, array := range arrays {
+		scrapeCtx, cancel := context.WithTimeout(ctx, interval)
+		inventory, err := scrapeInventory(scrapeCtx, array, listVolumeRecords(controller.records, name))
+		cancel()
+		if err != nil {
+			klog.ErrorS(err, "error scraping array inventory", "array", name)
+			InventoryMetrics.SetScrapeFailed(name)
+			continue
+		}
+		InventoryMetrics.SetInventory(name, inventory, time.Now())
+	}
+}
+
+// scrapeInventory: Read the pools, the recorded volumes, the controllers with their ports and the disk groups of an array
+func scrapeInventory(ctx context.Context, array inventoryArray, records []*volumeRecord) (*exporter.ArrayInventory, error) {
+	ctx = context.WithValue(ctx, client.ContextBasicAuth, client.BasicAuth{
+		UserName: array.username,
+		Password: array.password,
+	})
+	var apiClient *client.APIClient
+	var err error
+	for _, address := range array.addresses {
+		ipAddress, protocol := storageapitypes.GetAddressAndProtocol(address, "")
+		apiClient, err = storageapitypes.Login(ctx, &storageapitypes.Config{
+			MCIpAddress: ipAddress,
+			MCProtocol:  protocol,
+			MCUsername:  array.username,
+			MCPassword:  array.password,
+		})
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer apiClient.DefaultApi.LogoutGet(ctx).Execute()
+
+	inventory := &exporter.ArrayInventory{}
+
+	pools, _, err := apiClient.DefaultApi.ShowPoolsGet(ctx).Execute()
+	if err = responseError(pools, err); err != nil {
+		return nil, fmt.Errorf("show pools: %w", err)
+	}
+	volumes := showRecordedVolumes(ctx, apiClient, records)
+	for _, pool := range pools.GetPools() {
+		total := blocksToBytes(pool.GetTotalSizeNumeric(), pool.GetBlocksize())
+		available := blocksToBytes(pool.GetTotalAvailNumeric(), pool.GetBlocksize())
+		// the array reports no committed size, only the sizes of the volumes recorded by the driver are known
+		driverProvisioned := int64(0)
+		for _, volume := range volumes {
+			if volume.Pool == pool.GetName() {
+				driverProvisioned += volume.Size
+			}
+		}
+		inventory.Pools = append(inventory.Pools, exporter.PoolInventory{
+			Name:              pool.GetName(),
+			Total:             total,
+			Allocated:         total - available,
+			Available:         available,
+			DriverProvisioned: driverProvisioned,
+			Overcommit:        strings.EqualFold(pool.GetOvercommit(), "Enabled"),
+			Overcommitted:     strings.EqualFold(pool.GetOverCommitted(), "True"),
+		})
+	}
+	inventory.Volumes = volumes
+
+	controllers, _, err := apiClient.DefaultApi.ShowControllersGet(ctx).Execute()
+	if err = responseError(controllers, err); err != nil {
+		return nil, fmt.Errorf("show controllers: %w", err)
+	}
+	for _, controller := range controllers.GetControllers() {
+		inventory.Controllers = append(inventory.Controllers, exporter.ComponentHealth{
+			Name:   controller.GetControllerId(),
+			Health: healthValue(controller.GetHealth(), controller.HealthNumeric),
+		})
+		for _, port := range controller.GetPort() {
+			inventory.Ports = append(inventory.Ports, exporter.PortHealth{
+				Controller: controller.GetControllerId(),
+				Port:       port.GetPort(),
+				Type:       port.GetPortType(),
+				Health:     healthValue(port.GetHealth(), port.HealthNumeric),
+			})
+		}
+	}
+
+	diskGroups, _, err := apiClient.DefaultApi.ShowDiskGroupsGet(ctx).Execute()
+	if err = responseError(diskGroups, err); err != nil {
+		return nil, fmt.Errorf("show disk-groups: %w", err)
+	}
+	for _, diskGroup := range diskGroups.GetDiskGroups() {
+		inventory.DiskGroups = append(inventory.DiskGroups, exporter.DiskGroupState{
+			Name:   diskGroup.GetName(),
+			Pool:   diskGroup.GetPool(),
+			Status: diskGroup.GetStatus(),
+			Health: healthValue(diskGroup.GetHealth(), diskGroup.HealthNumeric),
+		})
+	}
+	return inventory, nil
+}
+
+// showRecordedVolumes: Read the size of the recorded volumes, one at a time when some of them no longer exist
+func showRecordedVolumes(ctx context.Context, apiClient *client.APIClient, records []*volumeRecord) []exporter.VolumeInventory {
+	if len(records) == 0 {
+		return nil
+	}
+	pvNames := map[string]string{}
+	for _, record := range records {
+		volumeName, _ := common.VolumeIdGetName(record.VolumeId)
+		pvNames[volumeName] = record.PVName
+	}
+
+	show := func(names []string) ([]client.VolumesResourceInner, error) {
+		response, _, err := apiClient.DefaultApi.ShowVolumesNamesGet(ctx, strings.Join(names, ",")).Execute()
+		if err = responseError(response, err); err != nil {
+			return nil, err
+		}
+		return response.GetVolumes(), nil
+	}
+	names := make([]string, 0, len(pvNames))
+	for name := range pvNames {
+		names = append(names, name)
+	}
+	resources, err := show(names)
+	if err != nil {
+		resources = nil
+		for _, name := range names {
+			if volume, err := show([]string{name}); err == nil {
+				resources = append(resources, volume...)
+			} else {
+				klog.V(4).InfoS("recorded volume not found", "volume", name, "error", err)
+			}
+		}
+	}
+
+	volumes := []exporter.VolumeInventory{}
+	for _, resource := range resources {
+		volumes = append(volumes, exporter.VolumeInventory{
+			Name: resource.GetVolumeName(),
+			PV:   pvNames[resource.GetVolumeName()],
+			Pool: resource.GetStoragePoolName(),
+			Size: blocksToBytes(resource.GetSizeNumeric(), resource.GetBlocksize()),
+		})
+	}
+	return volumes
+}
+
+// blocksToBytes: Convert a size reported in blocks, which are 512 bytes unless the block size is reported
+func blocksToBytes(blocks int64, blockSize int64) int64 {
+	if blockSize == 0 {
+		blockSize = 512
+	}
+	return blocks * blockSize
+}
+
+// responseError: Return the request error, or the error reported in the status of the response
+func responseError(response interface {
+	GetStatus() []client.StatusResourceInner
+}, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, apiStatus := range response.GetStatus() {
+		if apiStatus.GetResponseTypeNumeric() != 0 {
+			return errors.New(apiStatus.GetResponse())
+		}
+	}
+	return nil
+}
+
+// healthValue: The health numeric value of an object, derived from its health string when it is not reported
+func healthValue(health string, numeric *int64) int64 {
+	if numeric != nil {
+		return *numeric
+	}
+	if value, ok := healthValues[health]; ok {
+		return value
+	}
+	return healthValues["Unknown"]
+}
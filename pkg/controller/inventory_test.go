@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/exporter"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/simulator"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestScrapeInventory(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+	store := newFileRecordStore(t.TempDir())
+
+	client := storageapi.NewClient()
+	client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(client.Login(context.Background())).To(Succeed())
+	for _, name := range []string{"vol1", "vol2"} {
+		_, _, err := client.CreateVolume(name, "1GiB", simulator.DefaultPool)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	array := arrayName(sim.URL())
+	saveVolumeRecord(store, common.VolumeIdAugment("vol1", common.StorageProtocolISCSI, "600c0ff0001"), "pvc-1", sim.URL())
+	// the record of a volume deleted outside of the driver must not hide the others
+	saveVolumeRecord(store, common.VolumeIdAugment("gone", common.StorageProtocolISCSI, "600c0ff0002"), "pvc-2", sim.URL())
+	saveVolumeRecord(store, common.VolumeIdAugment("vol2", common.StorageProtocolISCSI, "600c0ff0003"), "pvc-3", "10.0.0.1")
+	records := listVolumeRecords(store, array)
+	g.Expect(records).To(HaveLen(2))
+
+	credentials := inventoryArray{addresses: []string{sim.URL()}, username: sim.Username, password: sim.Password}
+	inventory, err := scrapeInventory(context.Background(), credentials, records)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(inventory.Volumes).To(Equal([]exporter.VolumeInventory{{Name: "vol1", PV: "pvc-1", Pool: simulator.DefaultPool, Size: 1 << 30}}))
+	g.Expect(inventory.Pools).To(HaveLen(1))
+	pool := inventory.Pools[0]
+	g.Expect(pool.Name).To(Equal(simulator.DefaultPool))
+	g.Expect(pool.Total).To(Equal(simulator.DefaultPoolSize))
+	g.Expect(pool.Allocated).To(Equal(int64(2 << 30)))
+	g.Expect(pool.Available).To(Equal(simulator.DefaultPoolSize - 2<<30))
+	g.Expect(pool.DriverProvisioned).To(Equal(int64(1 << 30)))
+	g.Expect(pool.Overcommit).To(BeTrue())
+	g.Expect(pool.Overcommitted).To(BeFalse())
+	g.Expect(inventory.Controllers).To(Equal([]exporter.ComponentHealth{{Name: "A", Health: 0}, {Name: "B", Health: 0}}))
+	g.Expect(inventory.Ports).To(HaveLen(2))
+	g.Expect(inventory.Ports[1]).To(Equal(exporter.PortHealth{Controller: "B", Port: "B0", Type: "iSCSI", Health: 0}))
+	g.Expect(inventory.DiskGroups).To(Equal([]exporter.DiskGroupState{{Name: "dgA01", Pool: simulator.DefaultPool, Status: "FTOL", Health: 0}}))
+
+	removeVolumeRecord(store, "vol1")
+	g.Expect(listVolumeRecords(store, array)).To(HaveLen(1))
+
+	credentials.password = "wrong"
+	_, err = scrapeInventory(context.Background(), credentials, records)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRunInventory(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	controller := newTestController()
+	controller.records = newFileRecordStore(t.TempDir())
+	controller.rememberArray([]string{sim.URL()}, sim.Username, sim.Password)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the arrays are scraped from startup rather than after the first interval
+	go controller.runInventory(ctx, time.Hour)
+	g.Eventually(func() int {
+		return testutil.CollectAndCount(InventoryMetrics, "seagate_csi_array_pool_total_bytes")
+	}, 5*time.Second).Should(BeNumerically(">", 0))
+}
+
+func TestInventoryInterval(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(inventoryInterval()).To(Equal(defaultInventoryInterval))
+	t.Setenv(common.InventoryIntervalEnvVar, "30s")
+	g.Expect(inventoryInterval()).To(Equal(30 * time.Second))
+	t.Setenv(common.InventoryIntervalEnvVar, "0")
+	g.Expect(inventoryInterval()).To(BeZero())
+	t.Setenv(common.InventoryIntervalEnvVar, "often")
+	g.Expect(inventoryInterval()).To(Equal(defaultInventoryInterval))
+}
+
+func TestLoadArrayCredentials(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	controller := newTestController()
+	controller.client = storageapi.NewClient()
+	secret := t.TempDir()
+	writeKey := func(key string, value string) {
+		g.Expect(os.WriteFile(filepath.Join(secret, key), []byte(value), 0600)).To(Succeed())
+	}
+	writeKey(common.APIAddressConfigKey, sim.URL())
+	writeKey(common.UsernameSecretKey, sim.Username)
+
+	// an incomplete secret is refused
+	g.Expect(controller.loadArrayCredentials(secret)).To(MatchError(ContainSubstring(common.PasswordSecretKey)))
+	g.Expect(controller.inventoryArrays).To(BeEmpty())
+	g.Expect(controller.beginRoutine(&DriverCtx{}, "ControllerExpandVolume")).To(MatchError("missing API credentials"))
+
+	// the array of the secret is scraped from startup, without waiting for a request to log in
+	writeKey(common.PasswordSecretKey, sim.Password+"\n")
+	g.Expect(controller.loadArrayCredentials(secret)).To(Succeed())
+	g.Expect(controller.inventoryArrays).To(Equal(map[string]inventoryArray{
+		arrayName(sim.URL()): {addresses: []string{sim.URL()}, username: sim.Username, password: sim.Password},
+	}))
+
+	// requests without secrets log in with the configured credentials
+	ctx := &DriverCtx{}
+	g.Expect(controller.beginRoutine(ctx, "ControllerExpandVolume")).To(Succeed())
+	g.Expect(ctx.Credentials).To(Equal(controller.arrayCredentials))
+}
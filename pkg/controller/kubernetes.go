@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// namespacePath is where the service account of the pod holds the namespace the pod runs in
+const namespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// inClusterClient: Return a client of the Kubernetes API the controller runs in, created once and shared by the
+// node lookups and the record store
+var inClusterClient = sync.OnceValues(func() (kubernetes.Interface, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+})
+
+// inClusterNamespace: Return the namespace the controller runs in
+func inClusterNamespace() (string, error) {
+	namespace, err := os.ReadFile(namespacePath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(namespace)), nil
+}
@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	storageapitypes "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// mappingConfig: how a volume is mapped to the node it is published to
+type mappingConfig struct {
+	target string // initiators, host or hostGroup
+	name   string // host or host group the node initiators must belong to, any when empty
+	minLUN int
+	maxLUN int
+}
+
+// getMappingConfig: Read the mapping parameters of a volume, the publish secrets taking precedence over the
+// StorageClass parameters. Returns nil when none is set, so that the array library keeps choosing the LUN.
+func getMappingConfig(volumeContext map[string]string, secrets map[string]string) (*mappingConfig, error) {
+	get := func(key string) string {
+		if value, ok := secrets[key]; ok {
+			return value
+		}
+		return volumeContext[key]
+	}
+
+	target := get(common.MappingTargetConfigKey)
+	lunRange := get(common.MappingLUNRangeConfigKey)
+	if target == "" && lunRange == "" {
+		return nil, nil
+	}
+
+	config := &mappingConfig{target: target, minLUN: 1, maxLUN: common.MaximumLUN - 1}
+	switch target {
+	case "", common.MappingTargetInitiators:
+		config.target = common.MappingTargetInitiators
+	case common.MappingTargetHost:
+		config.name = get(common.MappingHostNameConfigKey)
+	case common.MappingTargetHostGroup:
+		config.name = get(common.MappingHostGroupConfigKey)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "parameter %s must be one of %s, %s or %s, got %q", common.MappingTargetConfigKey,
+			common.MappingTargetInitiators, common.MappingTargetHost, common.MappingTargetHostGroup, target)
+	}
+
+	if lunRange != "" {
+		var err error
+		config.minLUN, config.maxLUN, err = parseLUNRange(lunRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// parseLUNRange: Parse a "first-last" range of LUNs, the driver never uses LUN 0
+func parseLUNRange(value string) (int, int, error) {
+	invalid := status.Errorf(codes.InvalidArgument, "parameter %s must be a range of LUNs between 1 and %d such as 100-199, got %q",
+		common.MappingLUNRangeConfigKey, common.MaximumLUN-1, value)
+	bounds := strings.SplitN(value, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, invalid
+	}
+	first, err1 := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	last, err2 := strconv.Atoi(strings.TrimSpace(bounds[1]))
+	if err1 != nil || err2 != nil || first < 1 || last >= common.MaximumLUN || first > last {
+		return 0, 0, invalid
+	}
+	return first, last, nil
+}
+
+// mappingTargets: Return what the volume is mapped to on the array, the node initiators themselves, or the host or
+// host group holding them using the array "host.*" and "host-group.*.*" notations
+func (driver *Controller) mappingTargets(config *mappingConfig, initiators []string) ([]string, error) {
+	if config.target == common.MappingTargetInitiators {
+		return initiators, nil
+	}
+
+	hostGroup, host := "", ""
+	var err error
+	for _, initiator := range initiators {
+		if hostGroup, host, err = driver.client.GetInitiatorHostGroup(initiator); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "initiator(s) %v not found on the array: %v", initiators, err)
+	}
+
+	name, notation := host, "%s.*"
+	if config.target == common.MappingTargetHostGroup {
+		name, notation = hostGroup, "%s.*.*"
+	}
+	if name == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "initiator(s) %v are not part of a %s on the array", initiators, config.target)
+	}
+	if config.name != "" && config.name != name {
+		return nil, status.Errorf(codes.FailedPrecondition, "initiator(s) %v belong to %s %q instead of %q", initiators, config.target, name, config.name)
+	}
+	return []string{fmt.Sprintf(notation, name)}, nil
+}
+
+// chooseMappingLUN: Return the LUN the volume is already mapped to the node initiators with, otherwise the lowest LUN
+// of the configured range which is not used by any initiator of the targets, whether the mapping was made by the driver
+// or not. The LUN of a host or host group mapping must be free on every member, not only on the node being published to.
+func (driver *Controller) chooseMappingLUN(volumeName string, config *mappingConfig, initiators []string, targets []string) (int, bool, error) {
+	nodeInitiators := map[string]bool{}
+	for _, initiator := range initiators {
+		nodeInitiators[initiator] = true
+	}
+	members := append([]string{}, initiators...)
+	for _, target := range targets {
+		targetMembers, err := targetInitiators(driver.client, target)
+		if err != nil {
+			return 0, false, status.Errorf(codes.Unavailable, "unable to list the initiators of %s: %v", target, err)
+		}
+		for _, member := range targetMembers {
+			if !nodeInitiators[member] {
+				members = append(members, member)
+			}
+		}
+	}
+
+	used := map[int]bool{}
+	for _, initiator := range members {
+		volumes, _, err := driver.client.ShowHostMaps(initiator)
+		if err != nil {
+			klog.ErrorS(err, "error looking for host maps", "initiator", initiator)
+			continue
+		}
+		for _, volume := range volumes {
+			switch {
+			case volume.Name == volumeName && nodeInitiators[initiator]:
+				return volume.LUN, true, nil
+			case volume.Name != volumeName:
+				used[volume.LUN] = true
+			}
+		}
+	}
+	for lun := config.minLUN; lun <= config.maxLUN; lun++ {
+		if !used[lun] {
+			return lun, false, nil
+		}
+	}
+	return 0, false, status.Errorf(codes.ResourceExhausted, "no LUN available between %d and %d for volume %s on initiator(s) %v",
+		config.minLUN, config.maxLUN, volumeName, members)
+}
+
+// publishWithMapping: Map a volume according to its mapping parameters and return the chosen LUN and targets
+func (driver *Controller) publishWithMapping(volumeName string, config *mappingConfig, initiators []string) (string, []string, error) {
+	targets, err := driver.mappingTargets(config, initiators)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lun, mapped, err := driver.chooseMappingLUN(volumeName, config, initiators, targets)
+	if err != nil {
+		return "", nil, err
+	}
+	if mapped && config.target != common.MappingTargetInitiators {
+		return strconv.Itoa(lun), targets, nil
+	}
+
+	klog.InfoS("mapping volume", "volume", volumeName, "targets", targets, "lun", lun)
+	mapped = false
+	for _, target := range targets {
+		apiStatus, err := driver.client.MapVolume(volumeName, target, "rw", lun)
+		if err != nil {
+			return "", nil, err
+		}
+		switch {
+		case apiStatus.ResponseTypeNumeric == 0:
+			mapped = true
+		case apiStatus.ReturnCode == storageapitypes.VolumeNotFoundErrorCode:
+			return "", nil, status.Errorf(codes.NotFound, "volume %s not found", volumeName)
+		case apiStatus.ReturnCode == storageapitypes.LUNOverlapErrorCode:
+			return "", nil, status.Errorf(codes.Aborted, "LUN %d of volume %s is already in use by %s", lun, volumeName, target)
+		default:
+			klog.ErrorS(nil, "mapping error", "volume", volumeName, "target", target, "lun", lun, "response", apiStatus.Response)
+		}
+	}
+	if !mapped {
+		return "", nil, status.Errorf(codes.Internal, "error mapping volume (%s), no target was mapped successfully", volumeName)
+	}
+	return strconv.Itoa(lun), targets, nil
+}
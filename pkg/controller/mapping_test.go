@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/simulator"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetMappingConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config, err := getMappingConfig(map[string]string{}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config).To(BeNil())
+
+	parameters := map[string]string{
+		common.MappingTargetConfigKey:    common.MappingTargetHostGroup,
+		common.MappingHostGroupConfigKey: "cluster",
+		common.MappingLUNRangeConfigKey:  "100-199",
+	}
+	config, err = getMappingConfig(parameters, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*config).To(Equal(mappingConfig{target: common.MappingTargetHostGroup, name: "cluster", minLUN: 100, maxLUN: 199}))
+
+	config, err = getMappingConfig(parameters, map[string]string{common.MappingTargetConfigKey: common.MappingTargetHost})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*config).To(Equal(mappingConfig{target: common.MappingTargetHost, minLUN: 100, maxLUN: 199}))
+
+	for _, lunRange := range []string{"100", "0-10", "10-255", "20-10", "a-b"} {
+		_, err = getMappingConfig(map[string]string{common.MappingLUNRangeConfigKey: lunRange}, nil)
+		g.Expect(status.Code(err)).To(Equal(codes.InvalidArgument), lunRange)
+	}
+	_, err = getMappingConfig(map[string]string{common.MappingTargetConfigKey: "port"}, nil)
+	g.Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+}
+
+func TestPublishWithMapping(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	controller := newTestController()
+	controller.client = storageapi.NewClient()
+	controller.client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(controller.client.Login(context.Background())).To(Succeed())
+
+	node1 := "iqn.1993-08.org.debian:01:node1"
+	node2 := "iqn.1993-08.org.debian:01:node2"
+	sim.AddHost("node1", "cluster", node1)
+	sim.AddHost("node2", "cluster", node2)
+	for _, name := range []string{"vol1", "vol2", "vol3"} {
+		_, _, err := controller.client.CreateVolume(name, "1GiB", simulator.DefaultPool)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// a volume mapped outside of the driver to another node of the group takes the first LUN of the range
+	_, err := controller.client.MapVolume("vol3", node2, "rw", 100)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &mappingConfig{target: common.MappingTargetHostGroup, name: "cluster", minLUN: 100, maxLUN: 101}
+	lun, targets, err := controller.publishWithMapping("vol1", config, []string{node1})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lun).To(Equal("101"))
+	g.Expect(targets).To(Equal([]string{"cluster.*.*"}))
+
+	// the host group mapping already covers the other node
+	lun, _, err = controller.publishWithMapping("vol1", config, []string{node2})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lun).To(Equal("101"))
+
+	_, _, err = controller.publishWithMapping("vol2", config, []string{node2})
+	g.Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+
+	_, _, err = controller.publishWithMapping("vol2", &mappingConfig{target: common.MappingTargetHostGroup, name: "other", minLUN: 1, maxLUN: 254}, []string{node2})
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+	lun, targets, err = controller.publishWithMapping("vol2", &mappingConfig{target: common.MappingTargetHost, minLUN: 1, maxLUN: 254}, []string{node2})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lun).To(Equal("1"))
+	g.Expect(targets).To(Equal([]string{"node2.*"}))
+}
+
+func TestUnpublishSharedMapping(t *testing.T) {
+	g := NewWithT(t)
+	sim := simulator.New()
+	defer sim.Close()
+
+	controller := newTestController()
+	controller.records = newFileRecordStore(t.TempDir())
+	controller.client = storageapi.NewClient()
+	controller.client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	g.Expect(controller.client.Login(context.Background())).To(Succeed())
+
+	node1 := "iqn.1993-08.org.debian:01:node1"
+	node2 := "iqn.1993-08.org.debian:01:node2"
+	sim.AddHost("node1", "cluster", node1)
+	sim.AddHost("node2", "cluster", node2)
+	volume, _, err := controller.client.CreateVolume("vol1", "1GiB", simulator.DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+	volumeID := common.VolumeIdAugment("vol1", common.StorageProtocolISCSI, volume.Wwn)
+
+	// a multi-node volume published to both nodes through their host group
+	publish := func(nodeID string, initiator string) {
+		_, err := controller.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+			VolumeId: volumeID,
+			NodeId:   nodeID,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+			},
+			VolumeContext: map[string]string{
+				common.StorageProtocolKey:     common.StorageProtocolISCSI,
+				common.MappingTargetConfigKey: common.MappingTargetHostGroup,
+				nodeID + "/" + common.GetTopologyInitiatorLabel(common.StorageProtocolISCSI) + "-0": initiator,
+			},
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	unpublish := func(nodeID string) {
+		_, err := controller.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{VolumeId: volumeID, NodeId: nodeID})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	mapped := func(initiator string) bool {
+		volumes, _, err := controller.client.ShowHostMaps(initiator)
+		g.Expect(err).NotTo(HaveOccurred())
+		return len(volumes) > 0
+	}
+	publish("worker-1", node1)
+	publish("worker-2", node2)
+
+	// the host group mapping still serves the other node
+	unpublish("worker-1")
+	g.Expect(mapped(node2)).To(BeTrue())
+
+	unpublish("worker-2")
+	g.Expect(mapped(node2)).To(BeFalse())
+	g.Expect(mapped(node1)).To(BeFalse())
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// NodeAddressResolver returns the address of the node service of a node, by node name
+type NodeAddressResolver func(ctx context.Context, nodeName string) (string, error)
+
+// newNodeAddressResolver: Resolve node service addresses from the Kubernetes node objects, whose addresses follow
+// the node when its IP changes. The node plugin runs in the host network, so its service listens on the node IP.
+func newNodeAddressResolver() NodeAddressResolver {
+	client, err := inClusterClient()
+	if err != nil {
+		klog.InfoS("Kubernetes API unavailable, node service addresses cannot be resolved", "err", err)
+		return func(ctx context.Context, nodeName string) (string, error) {
+			return "", err
+		}
+	}
+	return kubernetesNodeAddresses(client)
+}
+
+// kubernetesNodeAddresses: Resolve node service addresses with a Kubernetes client
+func kubernetesNodeAddresses(client kubernetes.Interface) NodeAddressResolver {
+	return func(ctx context.Context, nodeName string) (string, error) {
+		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		address := nodeInternalIP(node)
+		if address == "" {
+			return "", fmt.Errorf("node %s has no IP address", nodeName)
+		}
+		return address, nil
+	}
+}
+
+// nodeInternalIP: Return the internal IP of a node, or its external IP when it has none
+func nodeInternalIP(node *corev1.Node) string {
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP} {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
+
+// NodeLabelsResolver returns the labels of a node, by node name
+type NodeLabelsResolver func(ctx context.Context, nodeName string) (map[string]string, error)
+
+// newNodeLabelsResolver: Read node labels from the Kubernetes node objects, which carry the topology segments the
+// node plugin last registered, so they follow initiator changes on the node
+func newNodeLabelsResolver() NodeLabelsResolver {
+	client, err := inClusterClient()
+	if err != nil {
+		return func(ctx context.Context, nodeName string) (map[string]string, error) {
+			return nil, err
+		}
+	}
+	return kubernetesNodeLabels(client)
+}
+
+// kubernetesNodeLabels: Read node labels with a Kubernetes client
+func kubernetesNodeLabels(client kubernetes.Interface) NodeLabelsResolver {
+	return func(ctx context.Context, nodeName string) (map[string]string, error) {
+		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return node.Labels, nil
+	}
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/node_service"
+	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// how long node info is cached, nodes being upgraded in place
+	nodeInfoTTL = 10 * time.Minute
+	// how long an unreachable node service is not asked again, so that publishing does not wait for it every time
+	nodeInfoFailureTTL = time.Minute
+)
+
+// nodeInfoEntry: the cached node info of a node, or why it could not be read
+type nodeInfoEntry struct {
+	info    *pb.NodeInfo
+	err     error
+	expires time.Time
+}
+
+// legacyNodeInfo: what nodes which predate GetNodeInfo support
+var legacyNodeInfo = &pb.NodeInfo{
+	ApiVersion: 1,
+	Rpcs:       []string{"GetInitiators", "NotifyUnmap"},
+}
+
+// getNodeInfo: Return the version and capabilities of the node plugin, cached per node. Nodes which predate
+// GetNodeInfo are described by legacyNodeInfo.
+func (controller *Controller) getNodeInfo(ctx context.Context, nodeAddress string) (*pb.NodeInfo, error) {
+	controller.nodesMutex.Lock()
+	previous, ok := controller.nodeInfos[nodeAddress]
+	controller.nodesMutex.Unlock()
+	if ok && time.Now().Before(previous.expires) {
+		return previous.info, previous.err
+	}
+
+	entry := nodeInfoEntry{expires: time.Now().Add(nodeInfoTTL)}
+	clientConnection, err := controller.nodeServiceClient(nodeAddress)
+	if err == nil {
+		entry.info, err = node_service.GetNodeInfo(ctx, clientConnection)
+	}
+	if status.Code(err) == codes.Unimplemented {
+		entry.info, err = legacyNodeInfo, nil
+	}
+	if node_service.IsPlaintextPeer(err) {
+		err = status.Errorf(codes.FailedPrecondition, "node service of %s does not use TLS, the node runs a plugin released before the node service was secured", nodeAddress)
+	}
+	if err != nil {
+		entry.err = err
+		entry.expires = time.Now().Add(nodeInfoFailureTTL)
+	}
+	klog.V(2).InfoS("node info", "nodeAddress", nodeAddress, "version", entry.info.GetVersion(), "apiVersion", entry.info.GetApiVersion(),
+		"protocols", entry.info.GetProtocols(), "rpcs", entry.info.GetRpcs(), "err", err)
+
+	controller.nodesMutex.Lock()
+	controller.nodeInfos[nodeAddress] = entry
+	if previous.info != nil && entry.info != nil && !proto.Equal(previous.info, entry.info) {
+		// the node plugin was upgraded or its initiators changed, what was learned from it may be stale
+		for nodeID, address := range controller.nodeAddresses {
+			if address == nodeAddress {
+				controller.forgetNodeInitiators(nodeID)
+			}
+		}
+		controller.forgetNodeInitiators(nodeAddress)
+	}
+	controller.nodesMutex.Unlock()
+	return entry.info, err
+}
+
+// nodeSupports: Return true if the node service implements an RPC
+func nodeSupports(info *pb.NodeInfo, rpc string) bool {
+	for _, name := range info.GetRpcs() {
+		if name == rpc {
+			return true
+		}
+	}
+	return false
+}
+
+// rescanNode: Ask the node to probe for the LUN a volume was just mapped with, so that attaching does not wait for the
+// node to discover it. Failures are only logged, the node discovering the LUN by itself eventually.
+func (controller *Controller) rescanNode(ctx context.Context, nodeID string, protocol string, volumeWWN string, lun string) {
+	lunNumber, err := strconv.Atoi(lun)
+	if err != nil {
+		return
+	}
+	nodeAddress := controller.resolveNodeAddress(ctx, nodeID)
+	info, err := controller.getNodeInfo(ctx, nodeAddress)
+	if err != nil || !nodeSupports(info, "RescanLUN") {
+		klog.V(2).InfoS("node rescan skipped", "nodeID", nodeID, "nodeAddress", nodeAddress, "err", err)
+		return
+	}
+	clientConnection, err := controller.nodeServiceClient(nodeAddress)
+	if err != nil {
+		return
+	}
+	targets, err := node_service.RescanLUN(ctx, clientConnection, protocolInitiatorType(protocol), volumeWWN, int32(lunNumber))
+	klog.InfoS("node rescan", "nodeID", nodeID, "volumeWWN", volumeWWN, "lun", lunNumber, "targets", targets, "err", err)
+}
+
+// majorVersion: Return the major version of a driver version such as v1.10.0, empty for development builds
+func majorVersion(version string) string {
+	major := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+	if major == "" || major == "0" || strings.Trim(major, "0123456789") != "" {
+		return ""
+	}
+	return major
+}
+
+// checkNodeCompatibility: Refuse to publish a volume to a node running a plugin of another major version, to a node
+// whose node service predates TLS, or which has no initiator of the volume protocol. Nodes whose node service cannot be
+// reached are not checked, as their initiators may come from their topology.
+func (controller *Controller) checkNodeCompatibility(ctx context.Context, nodeID string, protocol string) error {
+	nodeAddress := controller.resolveNodeAddress(ctx, nodeID)
+	info, err := controller.getNodeInfo(ctx, nodeAddress)
+	if status.Code(err) == codes.FailedPrecondition {
+		klog.ErrorS(err, "node runs an incompatible legacy plugin, upgrade it", "nodeID", nodeID, "nodeAddress", nodeAddress)
+		return status.Errorf(codes.FailedPrecondition, "node %s is incompatible: %s", nodeID, status.Convert(err).Message())
+	}
+	if err != nil {
+		klog.InfoS("node compatibility not checked, node service unavailable", "nodeID", nodeID, "nodeAddress", nodeAddress)
+		return nil
+	}
+	if info.GetApiVersion() <= legacyNodeInfo.GetApiVersion() {
+		klog.InfoS("node runs an older node service, some operations are degraded", "nodeID", nodeID, "apiVersion", info.GetApiVersion())
+		return nil
+	}
+
+	nodeMajor, controllerMajor := majorVersion(info.GetVersion()), majorVersion(common.Version)
+	if nodeMajor != "" && controllerMajor != "" && nodeMajor != controllerMajor {
+		return status.Errorf(codes.FailedPrecondition, "node %s runs plugin version %s which is incompatible with controller version %s",
+			nodeID, info.GetVersion(), common.Version)
+	}
+	if protocol == "" {
+		protocol = common.StorageProtocolISCSI
+	}
+	for _, supported := range info.GetProtocols() {
+		if supported == protocol {
+			return nil
+		}
+	}
+	return status.Errorf(codes.FailedPrecondition, "node %s has no %s initiator, it supports %v", nodeID, protocol, info.GetProtocols())
+}
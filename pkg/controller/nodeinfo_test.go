@@ -0,0 +1,227 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testNodeService: a node service reporting the given node info, or predating GetNodeInfo when it is nil
+type testNodeService struct {
+	pb.UnimplementedNodeServiceServer
+	info    *pb.NodeInfo
+	rescans []*pb.MappedVolume
+	health  chan *pb.HealthEvent
+}
+
+func (s *testNodeService) GetNodeInfo(ctx context.Context, in *pb.NodeInfoRequest) (*pb.NodeInfo, error) {
+	if s.info == nil {
+		return s.UnimplementedNodeServiceServer.GetNodeInfo(ctx, in)
+	}
+	return s.info, nil
+}
+
+func (s *testNodeService) RescanLUN(ctx context.Context, in *pb.MappedVolume) (*pb.ScannedTargets, error) {
+	s.rescans = append(s.rescans, in)
+	return &pb.ScannedTargets{}, nil
+}
+
+func (s *testNodeService) WatchHealth(in *pb.HealthRequest, stream pb.NodeService_WatchHealthServer) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-s.health:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startTestNodeService: serve the node service on a local port, which the controller uses for every node
+func startTestNodeService(t *testing.T, service *testNodeService) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterNodeServiceServer(s, service)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	t.Setenv(common.NodeServicePortEnvVar, strconv.Itoa(lis.Addr().(*net.TCPAddr).Port))
+	t.Setenv(common.NodeServiceInsecureEnvVar, "true")
+}
+
+func TestCheckNodeCompatibility(t *testing.T) {
+	g := NewWithT(t)
+	version := common.Version
+	defer func() { common.Version = version }()
+	common.Version = "v1.10.0"
+
+	service := &testNodeService{info: &pb.NodeInfo{Version: "v1.9.0", ApiVersion: common.NodeServiceAPIVersion, Protocols: []string{common.StorageProtocolISCSI}}}
+	startTestNodeService(t, service)
+	ctx := context.Background()
+
+	controller := newTestController()
+	g.Expect(controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolISCSI)).To(Succeed())
+	err := controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolSAS)
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+	// node info is cached
+	service.info = &pb.NodeInfo{Version: "v2.0.0", ApiVersion: common.NodeServiceAPIVersion, Protocols: []string{common.StorageProtocolISCSI}}
+	g.Expect(controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolISCSI)).To(Succeed())
+	controller = newTestController()
+	err = controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolISCSI)
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+	// older nodes are not checked
+	service.info = nil
+	controller = newTestController()
+	g.Expect(controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolSAS)).To(Succeed())
+	info, err := controller.getNodeInfo(ctx, "127.0.0.1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.GetApiVersion()).To(Equal(int32(1)))
+
+	// nor are nodes whose node service is unreachable
+	g.Expect(controller.checkNodeCompatibility(ctx, "127.0.0.2", common.StorageProtocolSAS)).To(Succeed())
+}
+
+// useSelfSignedCertificate: Give the controller a node service certificate, which only has to be loadable
+func useSelfSignedCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: common.NodeServiceClientName},
+		NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	for name, data := range map[string][]byte{
+		"tls.crt": cert,
+		"ca.crt":  cert,
+		"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(common.NodeServiceTLSCertEnvVar, filepath.Join(dir, "tls.crt"))
+	t.Setenv(common.NodeServiceTLSKeyEnvVar, filepath.Join(dir, "tls.key"))
+	t.Setenv(common.NodeServiceTLSCAEnvVar, filepath.Join(dir, "ca.crt"))
+	t.Setenv(common.NodeServiceInsecureEnvVar, "false")
+}
+
+func TestCheckPlaintextNode(t *testing.T) {
+	g := NewWithT(t)
+	// a node running a plugin released before the node service used TLS
+	startTestNodeService(t, &testNodeService{info: &pb.NodeInfo{ApiVersion: common.NodeServiceAPIVersion}})
+	useSelfSignedCertificate(t)
+	ctx := context.Background()
+
+	controller := newTestController()
+	err := controller.checkNodeCompatibility(ctx, "127.0.0.1", common.StorageProtocolISCSI)
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	g.Expect(err).To(MatchError(ContainSubstring("does not use TLS")))
+
+	// the node is reported the same way while its node info is cached
+	_, err = controller.getNodeInfo(ctx, "127.0.0.1")
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+}
+
+func TestRescanNode(t *testing.T) {
+	g := NewWithT(t)
+	service := &testNodeService{info: &pb.NodeInfo{ApiVersion: common.NodeServiceAPIVersion, Rpcs: []string{"GetNodeInfo", "RescanLUN"}}}
+	startTestNodeService(t, service)
+	ctx := context.Background()
+
+	controller := newTestController()
+	controller.rescanNode(ctx, "127.0.0.1", common.StorageProtocolFC, "600c0ff0000000000000000000000001", "3")
+	g.Expect(service.rescans).To(HaveLen(1))
+	g.Expect(service.rescans[0].GetType()).To(Equal(pb.InitiatorType_FC))
+	g.Expect(service.rescans[0].GetLun()).To(Equal(int32(3)))
+
+	// nodes which do not implement the rescan discover the LUN by themselves
+	service.info = &pb.NodeInfo{ApiVersion: 2, Rpcs: []string{"GetNodeInfo"}}
+	controller = newTestController()
+	controller.rescanNode(ctx, "127.0.0.1", common.StorageProtocolFC, "600c0ff0000000000000000000000001", "4")
+	g.Expect(service.rescans).To(HaveLen(1))
+}
+
+func TestNodeInfoChangeForgetsInitiators(t *testing.T) {
+	g := NewWithT(t)
+	service := &testNodeService{info: &pb.NodeInfo{ApiVersion: common.NodeServiceAPIVersion, Protocols: []string{common.StorageProtocolISCSI}}}
+	startTestNodeService(t, service)
+	ctx := context.Background()
+
+	controller := newTestController()
+	controller.SetNodeAddressResolver(staticNodeAddresses(map[string]string{"worker-1": "127.0.0.1"}))
+	volumeContext := map[string]string{"worker-1/iscsi-initiator-0": "iqn.1993-08.org.debian:01:node1"}
+	_, err := controller.getNodeInitiators(ctx, "worker-1", common.StorageProtocolISCSI, volumeContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = controller.getNodeInfo(ctx, "127.0.0.1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(controller.nodeInitiators).To(HaveLen(1))
+
+	// the same node info is reported again once the cached one expired
+	expire := func() {
+		entry := controller.nodeInfos["127.0.0.1"]
+		entry.expires = time.Now().Add(-time.Second)
+		controller.nodeInfos["127.0.0.1"] = entry
+	}
+	expire()
+	_, err = controller.getNodeInfo(ctx, "127.0.0.1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(controller.nodeInitiators).To(HaveLen(1))
+
+	// the node now reaches the arrays with FC as well
+	service.info = &pb.NodeInfo{ApiVersion: common.NodeServiceAPIVersion, Protocols: []string{common.StorageProtocolISCSI, common.StorageProtocolFC}}
+	expire()
+	_, err = controller.getNodeInfo(ctx, "127.0.0.1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(controller.nodeInitiators).To(BeEmpty())
+}
@@ -16,44 +16,95 @@ import (
 	"k8s.io/klog/v2"
 )
 
-// Extract available SAS addresses for Nodes from topology segments
-// This will contain all SAS initiators for all nodes unless the storage class
-// has specified allowed or preferred topologies
+// Extract the SAS, FC and iSCSI initiators of the nodes which can reach the array using the storage protocol from
+// their topology segments. This will contain all SAS initiators for all nodes unless the storage class has specified
+// allowed or preferred topologies. The volume is accessible from every node publishing the protocol segment, rather
+// than from the nodes requested, so that nodes added later can use it: the node ID and initiators stay out of its topology.
 func parseTopology(topologies []*csi.Topology, storageProtocol string, parameters *map[string]string) ([]*csi.Topology, error) {
 	klog.V(5).Infof("parseTopology: %v", topologies)
 
-	accessibleTopology := []*csi.Topology{}
-	hasInitiators := false
+	reachable, legacy := 0, 0
+	seen := map[string]bool{}
 	for _, topo := range topologies {
 
 		segments := topo.GetSegments()
 
+		// preferred topologies are also part of the requisite ones
+		id := fmt.Sprint(segments)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if !isTopologyReachable(segments, storageProtocol) {
+			klog.V(2).InfoS("skipping topology which cannot reach the array", "protocol", storageProtocol, "segments", segments)
+			continue
+		}
+
 		nodeID := segments[common.TopologyNodeIDKey]
-		hasInitiators = false
 		for key, val := range segments {
-			if strings.Contains(key, common.TopologySASInitiatorLabel) || strings.Contains(key, common.TopologyFCInitiatorLabel) {
-				hasInitiators = true
+			if strings.Contains(key, common.TopologySASInitiatorLabel) || strings.Contains(key, common.TopologyFCInitiatorLabel) ||
+				strings.Contains(key, common.TopologyISCSIInitiatorLabel) ||
+				strings.HasPrefix(key, common.TopologyInitiatorPrefix+"/"+common.TopologyInitiatorCountLabel+"-") {
 				newKey := strings.TrimPrefix(key, common.TopologyInitiatorPrefix)
 				// insert the node ID into the key so we can retrieve the node specific addresses after scheduling by the CO
 				newKey = nodeID + newKey
-				(*parameters)[newKey] = val
+				(*parameters)[newKey] = common.GetInitiatorFromTopology(val)
 			}
 		}
-		if hasInitiators {
-			accessibleTopology = append(accessibleTopology, topo)
+		reachable++
+		if _, ok := segments[common.GetTopologyProtocolKey(storageProtocol)]; !ok {
+			legacy++
 		}
+	}
+	if reachable == 0 {
+		return nil, status.Errorf(codes.ResourceExhausted, "no accessible topology can reach the array using protocol (%s)", storageProtocol)
+	}
+	if legacy > 0 {
+		// nodes which predate protocol segments would not match the protocol segment, leave the volume unconstrained
+		klog.V(2).InfoS("nodes without protocol segments can reach the array, volume topology not set", "protocol", storageProtocol, "nodes", legacy)
+		return nil, nil
+	}
+	return []*csi.Topology{{Segments: map[string]string{common.GetTopologyProtocolKey(storageProtocol): "true"}}}, nil
+}
 
+// isTopologyReachable: Return true if the node publishing the segments can reach the array using the storage protocol.
+// Nodes which predate protocol segments only published their SAS and FC addresses.
+func isTopologyReachable(segments map[string]string, storageProtocol string) bool {
+	if reachable, ok := segments[common.GetTopologyProtocolKey(storageProtocol)]; ok {
+		return reachable == "true"
+	}
+	for _, protocol := range []string{common.StorageProtocolISCSI, common.StorageProtocolFC, common.StorageProtocolSAS} {
+		if _, ok := segments[common.GetTopologyProtocolKey(protocol)]; ok {
+			return false
+		}
+	}
+	switch storageProtocol {
+	case common.StorageProtocolSAS:
+		return hasTopologyLabel(segments, common.TopologySASInitiatorLabel)
+	case common.StorageProtocolFC:
+		return hasTopologyLabel(segments, common.TopologyFCInitiatorLabel)
 	}
-	if len(accessibleTopology) == 0 {
-		accessibleTopology = nil
+	return true
+}
+
+// hasTopologyLabel: Return true if one of the segment keys contains the label
+func hasTopologyLabel(segments map[string]string, label string) bool {
+	for key := range segments {
+		if strings.Contains(key, label) {
+			return true
+		}
 	}
-	return accessibleTopology, nil
+	return false
 }
 
 // CreateVolume creates a new volume from the given request. The function is idempotent.
 func (controller *Controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
 
 	parameters := req.GetParameters()
+	if parameters == nil {
+		parameters = map[string]string{}
+	}
 
 	volumeName, err := common.TranslateName(req.GetName(), parameters[common.VolumePrefixKey])
 	if err != nil {
@@ -72,6 +123,19 @@ func (controller *Controller) CreateVolume(ctx context.Context, req *csi.CreateV
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("CreateVolume Volume capabilities not valid: %v", err))
 	}
 
+	if _, err := getMappingConfig(parameters, nil); err != nil {
+		return nil, err
+	}
+
+	var accessibleTopology []*csi.Topology
+	if requirements := req.GetAccessibilityRequirements(); requirements != nil {
+		topologies := append(append([]*csi.Topology{}, requirements.GetPreferred()...), requirements.GetRequisite()...)
+		accessibleTopology, err = parseTopology(topologies, storageProtocol, &parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	size := req.GetCapacityRange().GetRequiredBytes()
 	sizeStr := getSizeStr(size)
 	pool := parameters[common.PoolConfigKey]
@@ -104,12 +168,13 @@ func (controller *Controller) CreateVolume(ctx context.Context, req *csi.CreateV
 			}
 			apiStatus, err2 := controller.client.CopyVolume(sourceName, volumeName, parameters[common.PoolConfigKey])
 			if err2 != nil {
+				return nil, err2
+			} else if apiStatus.ResponseTypeNumeric != 0 {
 				klog.Infof("-- CopyVolume apiStatus.ReturnCode %v", apiStatus.ReturnCode)
-				if apiStatus != nil && apiStatus.ReturnCode == storageapitypes.SnapshotNotFoundErrorCode {
+				if apiStatus.ReturnCode == storageapitypes.SnapshotNotFoundErrorCode {
 					return nil, status.Errorf(codes.NotFound, "Snapshot source (%s) not found", sourceId)
-				} else {
-					return nil, err2
 				}
+				return nil, status.Errorf(codes.Unknown, "Error copying volume: %s", apiStatus.Response)
 			}
 
 		} else {
@@ -138,23 +203,28 @@ func (controller *Controller) CreateVolume(ctx context.Context, req *csi.CreateV
 		if err1 != nil {
 			klog.Errorf("++ GetTargetId error: %v", err1)
 		}
-		req.GetParameters()["iqn"] = targetId
+		parameters["iqn"] = targetId
 		portals, err2 := controller.client.GetPortals()
 		if err2 != nil {
 			klog.Errorf("++ GetPortals error: %v", err2)
 		}
-		req.GetParameters()["portals"] = portals
+		parameters["portals"] = portals
 		klog.V(2).Infof("Storing iSCSI iqn: %s, portals: %v", targetId, portals)
 	}
 
+	// The node uses the PV name to label the filesystem
+	parameters[common.PVNameConfigKey] = req.GetName()
+
 	volumeId := common.VolumeIdAugment(volumeName, storageProtocol, wwn)
+	saveVolumeRecord(controller.records, volumeId, req.GetName(), req.GetSecrets()[common.APIAddressConfigKey])
 
 	volume := &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
-			VolumeId:      volumeId,
-			VolumeContext: parameters,
-			CapacityBytes: req.GetCapacityRange().GetRequiredBytes(),
-			ContentSource: req.GetVolumeContentSource(),
+			VolumeId:           volumeId,
+			VolumeContext:      parameters,
+			CapacityBytes:      req.GetCapacityRange().GetRequiredBytes(),
+			ContentSource:      req.GetVolumeContentSource(),
+			AccessibleTopology: accessibleTopology,
 		},
 	}
 
@@ -178,6 +248,7 @@ func (controller *Controller) DeleteVolume(ctx context.Context, req *csi.DeleteV
 		if respStatus != nil {
 			if respStatus.ReturnCode == storageapitypes.VolumeNotFoundErrorCode {
 				klog.Infof("volume %s does not exist, assuming it has already been deleted", volumeName)
+				removeVolumeRecord(controller.records, volumeName)
 				return &csi.DeleteVolumeResponse{}, nil
 			} else if respStatus.ReturnCode == storageapitypes.VolumeHasSnapshot {
 				return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("volume %s cannot be deleted since it has snapshots", volumeName))
@@ -186,6 +257,7 @@ func (controller *Controller) DeleteVolume(ctx context.Context, req *csi.DeleteV
 		return nil, err
 	}
 
+	removeVolumeRecord(controller.records, volumeName)
 	klog.Infof("successfully deleted volume %s", volumeName)
 	return &csi.DeleteVolumeResponse{}, nil
 }
@@ -218,6 +290,14 @@ func isValidVolumeCapabilities(volCaps []*csi.VolumeCapability) error {
 		if !hasSupport(c) {
 			return fmt.Errorf("driver does not support access mode %v", c.GetAccessMode())
 		}
+		if err := common.ValidateMountFlags(c.GetMount().GetMountFlags()); err != nil {
+			return err
+		}
+		if fsType := c.GetMount().GetFsType(); fsType != "" {
+			if err := common.ValidateFsType(fsType); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
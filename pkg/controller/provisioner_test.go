@@ -0,0 +1,117 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseTopology(t *testing.T) {
+	g := NewWithT(t)
+
+	sasNode := &csi.Topology{Segments: map[string]string{
+		common.TopologyNodeIDKey:                                 "10.0.0.1",
+		common.GetTopologyProtocolKey(common.StorageProtocolSAS): "true",
+		common.TopologyInitiatorPrefix + "/sas-address-0":        "500605b00db5e3a0",
+	}}
+	iscsiNode := &csi.Topology{Segments: map[string]string{
+		common.TopologyNodeIDKey:                                   "10.0.0.2",
+		common.GetTopologyProtocolKey(common.StorageProtocolISCSI): "true",
+	}}
+	legacyNode := &csi.Topology{Segments: map[string]string{
+		common.TopologyNodeIDKey:                          "10.0.0.3",
+		common.TopologyInitiatorPrefix + "/sas-address-0": "500605b00db5e3b0",
+	}}
+	topologies := []*csi.Topology{sasNode, iscsiNode, legacyNode, sasNode}
+
+	parameters := map[string]string{}
+	accessible, err := parseTopology(topologies, common.StorageProtocolSAS, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accessible).To(BeNil())
+	g.Expect(parameters).To(Equal(map[string]string{
+		"10.0.0.1/sas-address-0": "500605b00db5e3a0",
+		"10.0.0.3/sas-address-0": "500605b00db5e3b0",
+	}))
+
+	// the volume topology only holds the protocol segment shared by every node which can reach the array
+	parameters = map[string]string{}
+	accessible, err = parseTopology([]*csi.Topology{sasNode, iscsiNode, sasNode}, common.StorageProtocolSAS, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accessible).To(Equal([]*csi.Topology{{Segments: map[string]string{
+		common.GetTopologyProtocolKey(common.StorageProtocolSAS): "true",
+	}}}))
+
+	parameters = map[string]string{}
+	accessible, err = parseTopology(topologies, common.StorageProtocolISCSI, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accessible).To(BeNil())
+	accessible, err = parseTopology([]*csi.Topology{iscsiNode}, common.StorageProtocolISCSI, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(accessible).To(Equal([]*csi.Topology{{Segments: map[string]string{
+		common.GetTopologyProtocolKey(common.StorageProtocolISCSI): "true",
+	}}}))
+
+	_, err = parseTopology([]*csi.Topology{iscsiNode}, common.StorageProtocolFC, &parameters)
+	g.Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+}
+
+func TestTopologyInitiators(t *testing.T) {
+	g := NewWithT(t)
+
+	iqn := "iqn.1993-08.org.debian:01:node1"
+	value, ok := common.GetTopologyCompliantInitiator(iqn)
+	g.Expect(ok).To(BeTrue())
+	node := &csi.Topology{Segments: map[string]string{
+		common.TopologyNodeIDKey:                                   "10.0.0.1",
+		common.GetTopologyProtocolKey(common.StorageProtocolISCSI): "true",
+		common.GetTopologyProtocolKey(common.StorageProtocolSAS):   "true",
+		common.TopologyInitiatorPrefix + "/iscsi-initiator-0":      value,
+		common.TopologyInitiatorPrefix + "/sas-address-1":          "500605b00db5e3a1",
+		common.TopologyInitiatorPrefix + "/sas-address-0":          "500605b00db5e3a0",
+	}}
+
+	parameters := map[string]string{}
+	_, err := parseTopology([]*csi.Topology{node}, common.StorageProtocolISCSI, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	initiators := func(nodeID string, protocol string) []string {
+		initiators, complete := topologyInitiators(nodeID, protocol, parameters)
+		g.Expect(complete).To(BeTrue())
+		return initiators
+	}
+	g.Expect(initiators("10.0.0.1", common.StorageProtocolISCSI)).To(Equal([]string{iqn}))
+	g.Expect(initiators("10.0.0.1", common.StorageProtocolSAS)).To(Equal([]string{"500605b00db5e3a0", "500605b00db5e3a1"}))
+	g.Expect(initiators("10.0.0.2", common.StorageProtocolSAS)).To(BeEmpty())
+
+	// an initiator which could not be published makes the list incomplete
+	node.Segments[common.TopologyInitiatorPrefix+"/"+common.GetTopologyInitiatorCountLabel(common.StorageProtocolSAS)] = "3"
+	node.Segments[common.TopologyInitiatorPrefix+"/"+common.GetTopologyInitiatorCountLabel(common.StorageProtocolISCSI)] = "1"
+	parameters = map[string]string{}
+	_, err = parseTopology([]*csi.Topology{node}, common.StorageProtocolISCSI, &parameters)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(initiators("10.0.0.1", common.StorageProtocolISCSI)).To(Equal([]string{iqn}))
+	sasInitiators, complete := topologyInitiators("10.0.0.1", common.StorageProtocolSAS, parameters)
+	g.Expect(sasInitiators).To(HaveLen(2))
+	g.Expect(complete).To(BeFalse())
+}
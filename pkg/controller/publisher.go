@@ -3,6 +3,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	storageapitypes "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
 
@@ -25,27 +29,79 @@ func (driver *Controller) ControllerPublishVolume(ctx context.Context, req *csi.
 		return nil, status.Error(codes.InvalidArgument, "cannot publish volume without capabilities")
 	}
 
-	nodeIP := req.GetNodeId()
+	nodeID := req.GetNodeId()
 	parameters := req.GetVolumeContext()
 
-	initiators, err := driver.GetNodeInitiators(ctx, nodeIP, parameters[common.StorageProtocolKey])
+	if err := driver.checkNodeCompatibility(ctx, nodeID, parameters[common.StorageProtocolKey]); err != nil {
+		return nil, err
+	}
+
+	initiators, err := driver.getNodeInitiators(ctx, nodeID, parameters[common.StorageProtocolKey], parameters)
 	if err != nil {
-		klog.ErrorS(err, "error getting node initiators", "node-ip", nodeIP, "storage-protocol", parameters[common.StorageProtocolKey])
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("Could not retrieve initiators for scheduled node(%s)", nodeIP))
+		klog.ErrorS(err, "error getting node initiators", "node-id", nodeID, "storage-protocol", parameters[common.StorageProtocolKey])
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("Could not retrieve initiators for scheduled node(%s)", nodeID))
 	}
 
 	volumeName, _ := common.VolumeIdGetName(req.GetVolumeId())
 
 	klog.InfoS("attach request", "initiator(s)", initiators, "volume", volumeName)
 
-	lun, err := driver.client.PublishVolume(volumeName, initiators)
+	mapping, err := getMappingConfig(parameters, req.GetSecrets())
+	if err != nil {
+		return nil, err
+	}
+
+	// the LUN space of the node is checked whatever the volume is mapped to, as every mapping takes a LUN of the node
+	if err := driver.checkLUNSpace(volumeName, initiators); err != nil {
+		return nil, err
+	}
+
+	var lun string
+	targets := initiators
+	if mapping == nil {
+		mapping = &mappingConfig{target: common.MappingTargetInitiators}
+		lun, err = driver.client.PublishVolume(volumeName, initiators)
+	} else {
+		lun, targets, err = driver.publishWithMapping(volumeName, mapping, initiators)
+	}
 
 	if err != nil {
+		// the initiators may be out of date, they are read again when the publishing is retried
+		driver.nodesMutex.Lock()
+		driver.forgetNodeInitiators(nodeID)
+		driver.nodesMutex.Unlock()
 		return nil, err
 	}
 
+	record := &publishRecord{
+		VolumeId:        req.GetVolumeId(),
+		NodeId:          nodeID,
+		StorageProtocol: parameters[common.StorageProtocolKey],
+		Initiators:      initiators,
+		MappingTarget:   mapping.target,
+		Targets:         targets,
+		LUN:             lun,
+		Timestamp:       time.Now(),
+	}
+	if err := record.save(driver.records); err != nil {
+		// without a record, unpublishing could not remove host and host group mappings
+		klog.ErrorS(err, "error saving publish record", "volume", volumeName, "nodeID", nodeID)
+		return nil, status.Errorf(codes.Unavailable, "unable to record the publishing of volume %s to node %s: %v", volumeName, nodeID, err)
+	}
+
+	// volumes created before volume records were kept get one when they are published
+	saveVolumeRecord(driver.records, req.GetVolumeId(), parameters[common.PVNameConfigKey], req.GetSecrets()[common.APIAddressConfigKey])
+
+	volumeWWN, _ := common.VolumeIdGetWwn(req.GetVolumeId())
+	driver.rescanNode(ctx, nodeID, parameters[common.StorageProtocolKey], volumeWWN, lun)
+	driver.watchNodeHealth(nodeID)
+
 	return &csi.ControllerPublishVolumeResponse{
-		PublishContext: map[string]string{"lun": lun},
+		PublishContext: map[string]string{
+			"lun":                           lun,
+			common.MappingTargetConfigKey:   mapping.target,
+			common.MappingPublishContextKey: strings.Join(targets, ","),
+		},
 	}, err
 }
 
@@ -57,32 +113,159 @@ func (driver *Controller) ControllerUnpublishVolume(ctx context.Context, req *cs
 
 	volumeName, _ := common.VolumeIdGetName(req.GetVolumeId())
 	volumeWWN, _ := common.VolumeIdGetWwn(req.GetVolumeId())
-	nodeIP := req.GetNodeId()
+	nodeID := req.GetNodeId()
 	storageProtocol, err := common.VolumeIdGetStorageProtocol(req.GetVolumeId())
 	if err != nil {
 		klog.ErrorS(err, "No storage protocol found in ControllerUnpublishVolume", "storage protocol", storageProtocol, "volume ID:", req.GetVolumeId())
 		return nil, err
 	}
 
-	initiators, err := driver.GetNodeInitiators(ctx, nodeIP, storageProtocol)
+	// the initiators recorded when publishing are unmapped without contacting the node, which may be gone
+	record, err := loadPublishRecord(driver.records, volumeName, nodeID)
 	if err != nil {
-		klog.ErrorS(err, "error getting initiators from the node", "nodeIP", nodeIP, "storageProtocol", storageProtocol)
+		klog.ErrorS(err, "error reading publish record", "volume", volumeName, "nodeID", nodeID)
+		return nil, status.Errorf(codes.Unavailable, "unable to read the publish record of volume %s on node %s: %v", volumeName, nodeID, err)
 	}
 
-	klog.InfoS("unmapping volume from initiator", "volumeName", volumeName, "initiators", initiators)
-	for _, initiator := range initiators {
-		status, err := driver.client.UnmapVolume(volumeName, initiator)
+	unmapped := false
+	if record != nil {
+		targets := driver.unsharedTargets(volumeName, nodeID, record.Targets)
+		klog.InfoS("unmapping volume from recorded targets", "volumeName", volumeName, "mappingTarget", record.MappingTarget, "targets", targets)
+		unmapped, err = driver.unmapTargets(volumeName, targets)
+	} else {
+		initiators, err := driver.getNodeInitiators(ctx, nodeID, storageProtocol, nil)
 		if err != nil {
-			if status != nil && status.ReturnCode == storageapitypes.UnmapFailedErrorCode {
-				klog.Info("unmap failed, assuming volume is already unmapped")
-			} else {
-				klog.Errorf("unknown error while unmapping initiator %s: %v", initiator, err)
-			}
-		} else {
-			driver.NotifyUnmap(ctx, nodeIP, volumeWWN)
+			klog.ErrorS(err, "error getting initiators from the node", "nodeID", nodeID, "storageProtocol", storageProtocol)
+			return nil, status.Errorf(codes.Unavailable, "no publish record for volume %s on node %s and the node initiators could not be retrieved: %v", volumeName, nodeID, err)
 		}
+
+		// host and host group mappings are always recorded, only the initiators of the node can be unmapped
+		klog.InfoS("unmapping volume from initiator", "volumeName", volumeName, "initiators", initiators)
+		unmapped, err = driver.unmapTargets(volumeName, initiators)
+	}
+	if unmapped {
+		driver.NotifyUnmap(ctx, driver.resolveNodeAddress(ctx, nodeID), volumeWWN)
+	}
+	if err != nil {
+		// the record is kept so that the unmapping is retried
+		return nil, err
 	}
+	if err := removePublishRecord(driver.records, volumeName, nodeID); err != nil {
+		klog.ErrorS(err, "error removing publish record", "volume", volumeName, "nodeID", nodeID)
+		return nil, status.Errorf(codes.Unavailable, "unable to remove the publish record of volume %s on node %s: %v", volumeName, nodeID, err)
+	}
+	driver.forgetVolumeHealth(volumeName, nodeID)
 
 	klog.Infof("successfully unmapped volume %s from all initiators", volumeName)
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
+
+// unmapTargets: Remove the mappings of a volume to initiators, or to "host.*" and "host-group.*.*" targets. Mappings
+// which do not exist are ignored. Returns true if a mapping was removed, and an error when a mapping may remain.
+func (driver *Controller) unmapTargets(volumeName string, targets []string) (bool, error) {
+	unmapped := false
+	var unmapErr error
+	for _, target := range targets {
+		apiStatus, err := driver.client.UnmapVolume(volumeName, target)
+		switch {
+		case err != nil:
+			klog.ErrorS(err, "error while unmapping volume", "volume", volumeName, "target", target)
+			unmapErr = status.Errorf(codes.Unavailable, "error unmapping volume %s from %s: %v", volumeName, target, err)
+		case apiStatus.ResponseTypeNumeric == 0:
+			unmapped = true
+		case apiStatus.ReturnCode == storageapitypes.UnmapFailedErrorCode || apiStatus.ReturnCode == storageapitypes.VolumeNotFoundErrorCode:
+			klog.InfoS("volume is not mapped", "volume", volumeName, "target", target, "response", apiStatus.Response)
+		default:
+			klog.ErrorS(nil, "error while unmapping volume", "volume", volumeName, "target", target, "response", apiStatus.Response)
+			unmapErr = status.Errorf(codes.Internal, "error unmapping volume %s from %s: %s", volumeName, target, apiStatus.Response)
+		}
+	}
+	return unmapped, unmapErr
+}
+
+// unsharedTargets: Return the recorded targets of a volume on a node which no other node the volume is published to
+// relies on. A host or host group mapping also serves the other nodes of the host or group.
+func (driver *Controller) unsharedTargets(volumeName string, nodeID string, targets []string) []string {
+	shared := map[string]bool{}
+	for _, other := range listPublishRecords(driver.records, volumeName, "") {
+		if other.NodeId == nodeID {
+			continue
+		}
+		for _, target := range other.Targets {
+			shared[target] = true
+		}
+	}
+	result := []string{}
+	for _, target := range targets {
+		if shared[target] && strings.HasSuffix(target, ".*") {
+			klog.InfoS("keeping mapping used by other nodes", "volume", volumeName, "target", target)
+			continue
+		}
+		result = append(result, target)
+	}
+	return result
+}
+
+// checkLUNSpace: Fail with ResourceExhausted when every LUN the host can use is taken by other volumes, whether or not
+// they were mapped by the driver, rather than letting the array reject the mapping
+func (driver *Controller) checkLUNSpace(volumeName string, initiators []string) error {
+	luns := map[int]bool{}
+	for _, initiator := range initiators {
+		volumes, _, err := driver.client.ShowHostMaps(initiator)
+		if err != nil {
+			klog.ErrorS(err, "error looking for host maps", "initiator", initiator)
+			continue
+		}
+		for _, volume := range volumes {
+			if volume.Name == volumeName {
+				// already mapped, publishing again reuses the LUN
+				return nil
+			}
+			// the driver never maps volumes to LUN 0
+			if volume.LUN > 0 && volume.LUN < common.MaximumLUN {
+				luns[volume.LUN] = true
+			}
+		}
+	}
+	if len(luns) >= common.MaximumLUN-1 {
+		return status.Errorf(codes.ResourceExhausted, "no LUN available for volume %s: %d LUNs already mapped to initiator(s) %v", volumeName, len(luns), initiators)
+	}
+	return nil
+}
+
+// labelInitiators: Return the initiators of a node published in the topology segments the kubelet copied to the node
+// labels, as topologyInitiators does for the volume context
+func labelInitiators(nodeID string, protocol string, labels map[string]string) ([]string, bool) {
+	nodeKey := common.GetTopologyCompliantNodeID(nodeID) + "/"
+	segments := map[string]string{}
+	for key, value := range labels {
+		if name, found := strings.CutPrefix(key, common.TopologyInitiatorPrefix+"/"); found {
+			segments[nodeKey+name] = common.GetInitiatorFromTopology(value)
+		}
+	}
+	return topologyInitiators(nodeID, protocol, segments)
+}
+
+// topologyInitiators: Return the initiators of a node which parseTopology stored in the volume context. The second
+// value is false when the node counted more initiators than it could publish in its topology.
+func topologyInitiators(nodeID string, protocol string, volumeContext map[string]string) ([]string, bool) {
+	nodeKey := common.GetTopologyCompliantNodeID(nodeID) + "/"
+	prefix := nodeKey + common.GetTopologyInitiatorLabel(protocol) + "-"
+	keys := []string{}
+	for key := range volumeContext {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	initiators := []string{}
+	for _, key := range keys {
+		initiators = append(initiators, volumeContext[key])
+	}
+	// nodes which predate the initiator count published every initiator they could
+	if count, ok := volumeContext[nodeKey+common.GetTopologyInitiatorCountLabel(protocol)]; ok && count != strconv.Itoa(len(initiators)) {
+		return initiators, false
+	}
+	return initiators, true
+}
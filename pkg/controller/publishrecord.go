@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"k8s.io/klog/v2"
+)
+
+// publishRecord: what a volume was mapped to when it was published to a node, so that it can be unmapped without
+// contacting the node, which may be gone
+type publishRecord struct {
+	VolumeId        string    `json:"volumeId"`
+	NodeId          string    `json:"nodeId"`
+	StorageProtocol string    `json:"storageProtocol"`
+	Initiators      []string  `json:"initiators"`
+	MappingTarget   string    `json:"mappingTarget"`
+	Targets         []string  `json:"targets"`
+	LUN             string    `json:"lun"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// publishRecordKey: Return the key of the publish record of a volume on a node
+func publishRecordKey(volumeName string, nodeID string) string {
+	return fmt.Sprintf("publish-%s-%s.json", volumeName, common.GetTopologyCompliantNodeID(nodeID))
+}
+
+// save: Store the record as JSON, replacing any previous record of the volume on the node
+func (record *publishRecord) save(records RecordStore) error {
+	volumeName, _ := common.VolumeIdGetName(record.VolumeId)
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return records.save(publishRecordKey(volumeName, record.NodeId), data)
+}
+
+// loadPublishRecord: Read the publish record of a volume on a node, returns nil when the volume was published before
+// records were kept or was not published to the node
+func loadPublishRecord(records RecordStore, volumeName string, nodeID string) (*publishRecord, error) {
+	data, err := records.load(publishRecordKey(volumeName, nodeID))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	record := &publishRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// removePublishRecord: Delete the publish record of a volume on a node once it is unmapped
+func removePublishRecord(records RecordStore, volumeName string, nodeID string) error {
+	return records.remove(publishRecordKey(volumeName, nodeID))
+}
+
+// listPublishRecords: Return the publish records of a volume, of a node, or of every volume and node when both are empty
+func listPublishRecords(records RecordStore, volumeName string, nodeID string) []*publishRecord {
+	prefix := "publish-"
+	if volumeName != "" {
+		prefix += volumeName + "-"
+	}
+	found := records.list(prefix)
+	result := []*publishRecord{}
+	for _, key := range sortedKeys(found) {
+		record := &publishRecord{}
+		if err := json.Unmarshal(found[key], record); err != nil {
+			klog.ErrorS(err, "ignoring publish record", "key", key)
+			continue
+		}
+		// names may contain dashes, so the prefix can match records of other volumes
+		recordVolumeName, _ := common.VolumeIdGetName(record.VolumeId)
+		if (volumeName != "" && recordVolumeName != volumeName) || (nodeID != "" && record.NodeId != nodeID) {
+			continue
+		}
+		result = append(result, record)
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// recordLabel marks the config maps holding controller records
+	recordLabel         = common.PluginName + "/record"
+	recordKeyField      = "key"
+	recordDataField     = "record"
+	recordStoreTimeout  = 30 * time.Second
+	recordFileExtension = ".json"
+)
+
+// RecordStore keeps the publish and volume records of the controller by key, such as "publish-<volume>-<node>.json"
+type RecordStore interface {
+	save(key string, data []byte) error
+	// load returns nil when the record does not exist
+	load(key string) ([]byte, error)
+	remove(key string) error
+	// list returns the records whose key starts with the prefix
+	list(prefix string) map[string][]byte
+}
+
+// NewRecordStore: Keep the records in config maps of the controller namespace, so that they survive the controller
+// being rescheduled to another node, which is when they are needed to unmap the volumes of a failed node. Records
+// found in the run path, where earlier versions kept them, are moved to the config maps. Outside of Kubernetes, or
+// when the config maps cannot be read, the records are kept in the run path.
+func NewRecordStore(runPath string) RecordStore {
+	files := newFileRecordStore(runPath)
+	client, err := inClusterClient()
+	namespace := ""
+	if err == nil {
+		namespace, err = inClusterNamespace()
+	}
+	var store *configMapRecordStore
+	if err == nil {
+		store, err = newConfigMapRecordStore(client, namespace)
+	}
+	if err != nil {
+		klog.ErrorS(err, "unable to keep records in config maps, records are lost if the controller moves to another node", "runPath", runPath)
+		return files
+	}
+	moveRecords(files, store)
+	return store
+}
+
+// moveRecords: Move the records of a store to another, keeping the records the other store already holds
+func moveRecords(from RecordStore, to RecordStore) {
+	for key, data := range from.list("") {
+		if existing, _ := to.load(key); existing == nil {
+			if err := to.save(key, data); err != nil {
+				klog.ErrorS(err, "error moving record", "key", key)
+				continue
+			}
+		}
+		if err := from.remove(key); err != nil {
+			klog.ErrorS(err, "error removing moved record", "key", key)
+		}
+	}
+}
+
+// fileRecordStore keeps each record in a file of a directory
+type fileRecordStore struct {
+	dir string
+}
+
+func newFileRecordStore(dir string) *fileRecordStore {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		klog.ErrorS(err, "unable to create record directory", "dir", dir)
+	}
+	return &fileRecordStore{dir: dir}
+}
+
+func (store *fileRecordStore) save(key string, data []byte) error {
+	return os.WriteFile(filepath.Join(store.dir, key), data, 0644)
+}
+
+func (store *fileRecordStore) load(key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(store.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (store *fileRecordStore) remove(key string) error {
+	if err := os.Remove(filepath.Join(store.dir, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (store *fileRecordStore) list(prefix string) map[string][]byte {
+	paths, _ := filepath.Glob(filepath.Join(store.dir, "*"+recordFileExtension))
+	records := map[string][]byte{}
+	for _, path := range paths {
+		key := filepath.Base(path)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			klog.ErrorS(err, "ignoring record", "path", path)
+			continue
+		}
+		records[key] = data
+	}
+	return records
+}
+
+// configMapRecordStore keeps each record in a config map of its own, so that records never conflict and the size
+// limit of config maps applies to a single record. The records are read once and cached, as only the controller
+// changes them.
+type configMapRecordStore struct {
+	client    kubernetes.Interface
+	namespace string
+	records   map[string][]byte
+	mutex     sync.Mutex
+}
+
+func newConfigMapRecordStore(client kubernetes.Interface, namespace string) (*configMapRecordStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), recordStoreTimeout)
+	defer cancel()
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: recordLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	store := &configMapRecordStore{client: client, namespace: namespace, records: map[string][]byte{}}
+	for _, configMap := range configMaps.Items {
+		if key := configMap.Data[recordKeyField]; key != "" {
+			store.records[key] = []byte(configMap.Data[recordDataField])
+		}
+	}
+	klog.InfoS("records kept in config maps", "namespace", namespace, "records", len(store.records))
+	return store, nil
+}
+
+// configMapName: Config map names are DNS subdomains, which record keys may not be
+func configMapName(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return "exos-x-csi-record-" + hex.EncodeToString(digest[:])[:32]
+}
+
+func (store *configMapRecordStore) save(key string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), recordStoreTimeout)
+	defer cancel()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName(key),
+			Namespace: store.namespace,
+			Labels:    map[string]string{recordLabel: strings.SplitN(key, "-", 2)[0]},
+		},
+		Data: map[string]string{recordKeyField: key, recordDataField: string(data)},
+	}
+	configMaps := store.client.CoreV1().ConfigMaps(store.namespace)
+	_, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.records[key] = data
+	return nil
+}
+
+func (store *configMapRecordStore) load(key string) ([]byte, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.records[key], nil
+}
+
+func (store *configMapRecordStore) remove(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), recordStoreTimeout)
+	defer cancel()
+	err := store.client.CoreV1().ConfigMaps(store.namespace).Delete(ctx, configMapName(key), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.records, key)
+	return nil
+}
+
+func (store *configMapRecordStore) list(prefix string) map[string][]byte {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	records := map[string][]byte{}
+	for key, data := range store.records {
+		if strings.HasPrefix(key, prefix) {
+			records[key] = data
+		}
+	}
+	return records
+}
+
+// sortedKeys: Return the keys of records in order, so that they are processed in the same order as files were
+func sortedKeys(records map[string][]byte) []string {
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapRecordStore(t *testing.T) {
+	g := NewWithT(t)
+	client := fake.NewSimpleClientset()
+	configMaps := func() []string {
+		list, err := client.CoreV1().ConfigMaps("csi").List(context.Background(), metav1.ListOptions{LabelSelector: recordLabel})
+		g.Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, configMap := range list.Items {
+			names = append(names, configMap.Name)
+		}
+		return names
+	}
+
+	store, err := newConfigMapRecordStore(client, "csi")
+	g.Expect(err).NotTo(HaveOccurred())
+	record := &publishRecord{VolumeId: "vol_1##iscsi##600c0ff0001", NodeId: "worker-1", Targets: []string{"cluster.*.*"}}
+	g.Expect(record.save(store)).To(Succeed())
+	g.Expect(record.save(store)).To(Succeed())
+	saveVolumeRecord(store, record.VolumeId, "pvc-1", "https://10.0.0.1")
+	g.Expect(configMaps()).To(HaveLen(2))
+
+	// the records survive the controller moving to another node
+	restarted, err := newConfigMapRecordStore(client, "csi")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(listPublishRecords(restarted, "vol_1", "")).To(Equal([]*publishRecord{record}))
+	g.Expect(listVolumeRecords(restarted, "10.0.0.1")).To(HaveLen(1))
+
+	g.Expect(removePublishRecord(restarted, "vol_1", "worker-1")).To(Succeed())
+	g.Expect(removePublishRecord(restarted, "vol_1", "worker-1")).To(Succeed())
+	g.Expect(loadPublishRecord(restarted, "vol_1", "worker-1")).To(BeNil())
+	g.Expect(configMaps()).To(HaveLen(1))
+
+	// records kept in the run path by earlier versions are moved
+	files := newFileRecordStore(t.TempDir())
+	other := &publishRecord{VolumeId: "vol_2##iscsi##600c0ff0002", NodeId: "worker-2"}
+	g.Expect(other.save(files)).To(Succeed())
+	moveRecords(files, restarted)
+	g.Expect(files.list("")).To(BeEmpty())
+	g.Expect(loadPublishRecord(restarted, "vol_2", "worker-2")).To(Equal(other))
+	g.Expect(configMaps()).To(HaveLen(2))
+}
@@ -8,18 +8,39 @@ import (
 )
 
 type Collector struct {
-	csiRPCCall         *prometheus.CounterVec
-	csiRPCCallDuration *prometheus.CounterVec
+	csiRPCCall            *prometheus.CounterVec
+	csiRPCCallDuration    *prometheus.CounterVec
+	csiRPCCallSeconds     *prometheus.HistogramVec
+	csiRPCCallsInFlight   *prometheus.GaugeVec
+	arrayAPICallSeconds   *prometheus.HistogramVec
+	arrayAPICallsInFlight *prometheus.GaugeVec
 }
 
 const (
 	csiRPCCallMetric = "seagate_csi_rpc_call"
-	csiRPCCallHelp   = "How many CSI RPC calls have been executed"
+	csiRPCCallHelp   = "How many CSI RPC calls have been executed (deprecated, use seagate_csi_rpc_call_duration_seconds_count)"
 
 	csiRPCCallDurationMetric = "seagate_csi_rpc_call_duration"
-	csiRPCCallDurationHelp   = "The total duration of CSI RPC calls"
+	csiRPCCallDurationHelp   = "The total duration of CSI RPC calls (deprecated, use seagate_csi_rpc_call_duration_seconds_sum)"
+
+	csiRPCCallSecondsMetric = "seagate_csi_rpc_call_duration_seconds"
+	csiRPCCallSecondsHelp   = "How long CSI RPC calls take, by gRPC status code"
+
+	csiRPCCallsInFlightMetric = "seagate_csi_rpc_calls_in_flight"
+	csiRPCCallsInFlightHelp   = "How many CSI RPC calls are being executed"
+
+	arrayAPICallSecondsMetric = "seagate_csi_array_api_call_duration_seconds"
+	arrayAPICallSecondsHelp   = "How long array API commands take, success is false when the array could not be reached or answered with an HTTP error"
+
+	arrayAPICallsInFlightMetric = "seagate_csi_array_api_calls_in_flight"
+	arrayAPICallsInFlightHelp   = "How many array API commands are waiting for the array"
 )
 
+// CSI calls wait for the array and the nodes, provisioning and publishing may take tens of seconds
+var csiRPCCallBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+var arrayAPICallBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15}
+
 func NewCollector() *Collector {
 	return &Collector{
 		csiRPCCall: prometheus.NewCounterVec(
@@ -36,23 +57,92 @@ func NewCollector() *Collector {
 			},
 			[]string{"endpoint"},
 		),
+		csiRPCCallSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    csiRPCCallSecondsMetric,
+				Help:    csiRPCCallSecondsHelp,
+				Buckets: csiRPCCallBuckets,
+			},
+			[]string{"endpoint", "code"},
+		),
+		csiRPCCallsInFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: csiRPCCallsInFlightMetric,
+				Help: csiRPCCallsInFlightHelp,
+			},
+			[]string{"endpoint"},
+		),
+		arrayAPICallSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    arrayAPICallSecondsMetric,
+				Help:    arrayAPICallSecondsHelp,
+				Buckets: arrayAPICallBuckets,
+			},
+			[]string{"command", "success"},
+		),
+		arrayAPICallsInFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: arrayAPICallsInFlightMetric,
+				Help: arrayAPICallsInFlightHelp,
+			},
+			[]string{"command"},
+		),
 	}
 }
 
 func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	collector.csiRPCCall.Describe(ch)
 	collector.csiRPCCallDuration.Describe(ch)
+	collector.csiRPCCallSeconds.Describe(ch)
+	collector.csiRPCCallsInFlight.Describe(ch)
+	collector.arrayAPICallSeconds.Describe(ch)
+	collector.arrayAPICallsInFlight.Describe(ch)
 }
 
 func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
 	collector.csiRPCCall.Collect(ch)
 	collector.csiRPCCallDuration.Collect(ch)
+	collector.csiRPCCallSeconds.Collect(ch)
+	collector.csiRPCCallsInFlight.Collect(ch)
+	collector.arrayAPICallSeconds.Collect(ch)
+	collector.arrayAPICallsInFlight.Collect(ch)
 }
 
+// IncCSIRPCCall: Count a CSI call in the deprecated seagate_csi_rpc_call series
 func (collector *Collector) IncCSIRPCCall(method string, success bool) {
 	collector.csiRPCCall.WithLabelValues(method, fmt.Sprintf("%t", success)).Inc()
 }
 
+// AddCSIRPCCallDuration: Add the duration of a CSI call to the deprecated seagate_csi_rpc_call_duration series
 func (collector *Collector) AddCSIRPCCallDuration(method string, duration time.Duration) {
 	collector.csiRPCCallDuration.WithLabelValues(method).Add(float64(duration.Nanoseconds()) / 1000 / 1000 / 1000)
 }
+
+// TrackCSIRPCCall: Count a CSI call as in flight until the returned function is called with its gRPC status code,
+// which observes its duration. The deprecated series are updated as well.
+func (collector *Collector) TrackCSIRPCCall(method string) func(code string) {
+	start := time.Now()
+	inFlight := collector.csiRPCCallsInFlight.WithLabelValues(method)
+	inFlight.Inc()
+
+	return func(code string) {
+		duration := time.Since(start)
+		inFlight.Dec()
+		collector.csiRPCCallSeconds.WithLabelValues(method, code).Observe(duration.Seconds())
+		collector.IncCSIRPCCall(method, code == "OK")
+		collector.AddCSIRPCCallDuration(method, duration)
+	}
+}
+
+// TrackArrayAPICall: Count an array API command as in flight until the returned function is called, which observes
+// its duration
+func (collector *Collector) TrackArrayAPICall(command string) func(success bool) {
+	start := time.Now()
+	inFlight := collector.arrayAPICallsInFlight.WithLabelValues(command)
+	inFlight.Inc()
+
+	return func(success bool) {
+		inFlight.Dec()
+		collector.arrayAPICallSeconds.WithLabelValues(command, fmt.Sprintf("%t", success)).Observe(time.Since(start).Seconds())
+	}
+}
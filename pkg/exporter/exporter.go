@@ -77,6 +77,10 @@ func (exporter *Exporter) Serve() error {
 
 // Shutdown : Properly tear down server
 func (exporter *Exporter) Shutdown() error {
+	if exporter.server == nil {
+		// the exporter never started serving, e.g. its port was already in use
+		return nil
+	}
 	return exporter.server.Shutdown(context.Background())
 }
 
@@ -0,0 +1,39 @@
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// FsCheckCollector counts the results of filesystem checks and repairs done by the node plugin
+type FsCheckCollector struct {
+	fsCheck *prometheus.CounterVec
+}
+
+const (
+	fsCheckMetric = "seagate_csi_fs_check"
+	fsCheckHelp   = "How many filesystem checks and repairs have been executed, by result"
+)
+
+func NewFsCheckCollector() *FsCheckCollector {
+	return &FsCheckCollector{
+		fsCheck: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fsCheckMetric,
+				Help: fsCheckHelp,
+			},
+			[]string{"fstype", "policy", "result"},
+		),
+	}
+}
+
+func (collector *FsCheckCollector) Describe(ch chan<- *prometheus.Desc) {
+	collector.fsCheck.Describe(ch)
+}
+
+func (collector *FsCheckCollector) Collect(ch chan<- prometheus.Metric) {
+	collector.fsCheck.Collect(ch)
+}
+
+func (collector *FsCheckCollector) IncFsCheck(fsType string, policy string, result string) {
+	collector.fsCheck.WithLabelValues(fsType, policy, result).Inc()
+}
@@ -0,0 +1,80 @@
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NodeHealthCollector exports the device health events reported by the nodes and the resulting volume conditions
+type NodeHealthCollector struct {
+	healthEvents   *prometheus.CounterVec
+	volumeAbnormal *prometheus.GaugeVec
+	failedPaths    *prometheus.GaugeVec
+}
+
+const (
+	healthEventsMetric = "seagate_csi_node_health_events"
+	healthEventsHelp   = "How many device health events have been reported by the nodes, by volume, node, type and severity"
+
+	volumeAbnormalMetric = "seagate_csi_volume_abnormal"
+	volumeAbnormalHelp   = "Whether a published volume is in an abnormal condition on a node (1) or not (0)"
+
+	failedPathsMetric = "seagate_csi_volume_failed_paths"
+	failedPathsHelp   = "How many paths of a published volume are reported as failed by a node"
+)
+
+func NewNodeHealthCollector() *NodeHealthCollector {
+	return &NodeHealthCollector{
+		healthEvents: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: healthEventsMetric,
+				Help: healthEventsHelp,
+			},
+			[]string{"volume", "node", "type", "severity"},
+		),
+		volumeAbnormal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: volumeAbnormalMetric,
+				Help: volumeAbnormalHelp,
+			},
+			[]string{"volume", "node"},
+		),
+		failedPaths: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: failedPathsMetric,
+				Help: failedPathsHelp,
+			},
+			[]string{"volume", "node"},
+		),
+	}
+}
+
+func (collector *NodeHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	collector.healthEvents.Describe(ch)
+	collector.volumeAbnormal.Describe(ch)
+	collector.failedPaths.Describe(ch)
+}
+
+func (collector *NodeHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	collector.healthEvents.Collect(ch)
+	collector.volumeAbnormal.Collect(ch)
+	collector.failedPaths.Collect(ch)
+}
+
+func (collector *NodeHealthCollector) IncHealthEvent(volume string, node string, eventType string, severity string) {
+	collector.healthEvents.WithLabelValues(volume, node, eventType, severity).Inc()
+}
+
+func (collector *NodeHealthCollector) SetVolumeHealth(volume string, node string, abnormal bool, failedPaths int) {
+	value := 0.0
+	if abnormal {
+		value = 1
+	}
+	collector.volumeAbnormal.WithLabelValues(volume, node).Set(value)
+	collector.failedPaths.WithLabelValues(volume, node).Set(float64(failedPaths))
+}
+
+// DeleteVolume: Stop exporting the condition of a volume which is no longer published to a node
+func (collector *NodeHealthCollector) DeleteVolume(volume string, node string) {
+	collector.volumeAbnormal.DeleteLabelValues(volume, node)
+	collector.failedPaths.DeleteLabelValues(volume, node)
+}
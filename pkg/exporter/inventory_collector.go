@@ -0,0 +1,227 @@
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ArrayInventory is what was read from an array during one scrape of the controller
+type ArrayInventory struct {
+	Pools       []PoolInventory
+	Volumes     []VolumeInventory
+	Controllers []ComponentHealth
+	Ports       []PortHealth
+	DiskGroups  []DiskGroupState
+}
+
+// PoolInventory is the capacity of a pool, in bytes. DriverProvisioned only counts the volumes created by the driver,
+// volumes created outside of it are part of Allocated only.
+type PoolInventory struct {
+	Name              string
+	Total             int64
+	Allocated         int64
+	Available         int64
+	DriverProvisioned int64
+	Overcommit        bool
+	Overcommitted     bool
+}
+
+// VolumeInventory is the size of a volume, PV is empty for volumes created before volume records were kept
+type VolumeInventory struct {
+	Name string
+	PV   string
+	Pool string
+	Size int64
+}
+
+// ComponentHealth is the health of a controller, using the array health numeric values
+type ComponentHealth struct {
+	Name   string
+	Health int64
+}
+
+// PortHealth is the health of a host port of a controller
+type PortHealth struct {
+	Controller string
+	Port       string
+	Type       string
+	Health     int64
+}
+
+// DiskGroupState is the status (FTOL, CRIT, OFFL...) and health of a disk group
+type DiskGroupState struct {
+	Name   string
+	Pool   string
+	Status string
+	Health int64
+}
+
+// InventoryCollector exports the array inventories cached by the controller, so that Prometheus scrapes never reach
+// the arrays
+type InventoryCollector struct {
+	mu                    sync.Mutex
+	poolTotal             *prometheus.GaugeVec
+	poolAllocated         *prometheus.GaugeVec
+	poolAvailable         *prometheus.GaugeVec
+	poolDriverProvisioned *prometheus.GaugeVec
+	poolOvercommit        *prometheus.GaugeVec
+	poolOvercommitted     *prometheus.GaugeVec
+	volumeSize            *prometheus.GaugeVec
+	controllerHealth      *prometheus.GaugeVec
+	portHealth            *prometheus.GaugeVec
+	diskGroupHealth       *prometheus.GaugeVec
+	diskGroupStatus       *prometheus.GaugeVec
+	scrapeSuccess         *prometheus.GaugeVec
+	scrapeTimestamp       *prometheus.GaugeVec
+}
+
+const (
+	poolTotalMetric = "seagate_csi_array_pool_total_bytes"
+	poolTotalHelp   = "The size of a pool of an array"
+
+	poolAllocatedMetric = "seagate_csi_array_pool_allocated_bytes"
+	poolAllocatedHelp   = "How much of a pool is allocated to volumes and snapshots"
+
+	poolAvailableMetric = "seagate_csi_array_pool_available_bytes"
+	poolAvailableHelp   = "How much of a pool is available"
+
+	poolDriverProvisionedMetric = "seagate_csi_array_pool_driver_provisioned_bytes"
+	poolDriverProvisionedHelp   = "The total size of the volumes created by the driver in a pool, leaving out the volumes created outside of the driver, above the pool size when thin provisioning overcommits it"
+
+	poolOvercommitMetric = "seagate_csi_array_pool_overcommit_enabled"
+	poolOvercommitHelp   = "Whether a pool allows its volumes to exceed its size (1) or not (0)"
+
+	poolOvercommittedMetric = "seagate_csi_array_pool_overcommitted"
+	poolOvercommittedHelp   = "Whether the volumes of a pool exceed its size (1) or not (0)"
+
+	volumeSizeMetric = "seagate_csi_array_volume_size_bytes"
+	volumeSizeHelp   = "The size of a volume created by the driver, with the name of its persistent volume"
+
+	controllerHealthMetric = "seagate_csi_array_controller_health"
+	controllerHealthHelp   = "The health of an array controller: 0 OK, 1 degraded, 2 fault, 3 unknown, 4 not available"
+
+	portHealthMetric = "seagate_csi_array_port_health"
+	portHealthHelp   = "The health of a host port: 0 OK, 1 degraded, 2 fault, 3 unknown, 4 not available"
+
+	diskGroupHealthMetric = "seagate_csi_array_disk_group_health"
+	diskGroupHealthHelp   = "The health of a disk group: 0 OK, 1 degraded, 2 fault, 3 unknown, 4 not available"
+
+	diskGroupStatusMetric = "seagate_csi_array_disk_group_status"
+	diskGroupStatusHelp   = "The status of a disk group, as its status label"
+
+	scrapeSuccessMetric = "seagate_csi_array_scrape_success"
+	scrapeSuccessHelp   = "Whether the last scrape of an array by the controller succeeded (1) or not (0)"
+
+	scrapeTimestampMetric = "seagate_csi_array_scrape_timestamp_seconds"
+	scrapeTimestampHelp   = "When the exported inventory of an array was last read, as a unix timestamp"
+)
+
+func newArrayGaugeVec(name string, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		append([]string{"array"}, labels...),
+	)
+}
+
+func NewInventoryCollector() *InventoryCollector {
+	return &InventoryCollector{
+		poolTotal:             newArrayGaugeVec(poolTotalMetric, poolTotalHelp, "pool"),
+		poolAllocated:         newArrayGaugeVec(poolAllocatedMetric, poolAllocatedHelp, "pool"),
+		poolAvailable:         newArrayGaugeVec(poolAvailableMetric, poolAvailableHelp, "pool"),
+		poolDriverProvisioned: newArrayGaugeVec(poolDriverProvisionedMetric, poolDriverProvisionedHelp, "pool"),
+		poolOvercommit:        newArrayGaugeVec(poolOvercommitMetric, poolOvercommitHelp, "pool"),
+		poolOvercommitted:     newArrayGaugeVec(poolOvercommittedMetric, poolOvercommittedHelp, "pool"),
+		volumeSize:            newArrayGaugeVec(volumeSizeMetric, volumeSizeHelp, "pool", "volume", "pv"),
+		controllerHealth:      newArrayGaugeVec(controllerHealthMetric, controllerHealthHelp, "controller"),
+		portHealth:            newArrayGaugeVec(portHealthMetric, portHealthHelp, "controller", "port", "type"),
+		diskGroupHealth:       newArrayGaugeVec(diskGroupHealthMetric, diskGroupHealthHelp, "disk_group", "pool"),
+		diskGroupStatus:       newArrayGaugeVec(diskGroupStatusMetric, diskGroupStatusHelp, "disk_group", "pool", "status"),
+		scrapeSuccess:         newArrayGaugeVec(scrapeSuccessMetric, scrapeSuccessHelp),
+		scrapeTimestamp:       newArrayGaugeVec(scrapeTimestampMetric, scrapeTimestampHelp),
+	}
+}
+
+// inventoryVecs: The metrics replaced as a whole when an inventory is set
+func (collector *InventoryCollector) inventoryVecs() []*prometheus.GaugeVec {
+	return []*prometheus.GaugeVec{
+		collector.poolTotal,
+		collector.poolAllocated,
+		collector.poolAvailable,
+		collector.poolDriverProvisioned,
+		collector.poolOvercommit,
+		collector.poolOvercommitted,
+		collector.volumeSize,
+		collector.controllerHealth,
+		collector.portHealth,
+		collector.diskGroupHealth,
+		collector.diskGroupStatus,
+	}
+}
+
+func (collector *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, vec := range collector.inventoryVecs() {
+		vec.Describe(ch)
+	}
+	collector.scrapeSuccess.Describe(ch)
+	collector.scrapeTimestamp.Describe(ch)
+}
+
+func (collector *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, vec := range collector.inventoryVecs() {
+		vec.Collect(ch)
+	}
+	collector.scrapeSuccess.Collect(ch)
+	collector.scrapeTimestamp.Collect(ch)
+}
+
+// SetInventory: Replace the exported inventory of an array with the one read at the given time
+func (collector *InventoryCollector) SetInventory(array string, inventory *ArrayInventory, scraped time.Time) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, vec := range collector.inventoryVecs() {
+		vec.DeletePartialMatch(prometheus.Labels{"array": array})
+	}
+
+	for _, pool := range inventory.Pools {
+		collector.poolTotal.WithLabelValues(array, pool.Name).Set(float64(pool.Total))
+		collector.poolAllocated.WithLabelValues(array, pool.Name).Set(float64(pool.Allocated))
+		collector.poolAvailable.WithLabelValues(array, pool.Name).Set(float64(pool.Available))
+		collector.poolDriverProvisioned.WithLabelValues(array, pool.Name).Set(float64(pool.DriverProvisioned))
+		collector.poolOvercommit.WithLabelValues(array, pool.Name).Set(boolValue(pool.Overcommit))
+		collector.poolOvercommitted.WithLabelValues(array, pool.Name).Set(boolValue(pool.Overcommitted))
+	}
+	for _, volume := range inventory.Volumes {
+		collector.volumeSize.WithLabelValues(array, volume.Pool, volume.Name, volume.PV).Set(float64(volume.Size))
+	}
+	for _, controller := range inventory.Controllers {
+		collector.controllerHealth.WithLabelValues(array, controller.Name).Set(float64(controller.Health))
+	}
+	for _, port := range inventory.Ports {
+		collector.portHealth.WithLabelValues(array, port.Controller, port.Port, port.Type).Set(float64(port.Health))
+	}
+	for _, diskGroup := range inventory.DiskGroups {
+		collector.diskGroupHealth.WithLabelValues(array, diskGroup.Name, diskGroup.Pool).Set(float64(diskGroup.Health))
+		collector.diskGroupStatus.WithLabelValues(array, diskGroup.Name, diskGroup.Pool, diskGroup.Status).Set(1)
+	}
+	collector.scrapeSuccess.WithLabelValues(array).Set(1)
+	collector.scrapeTimestamp.WithLabelValues(array).Set(float64(scraped.Unix()))
+}
+
+// SetScrapeFailed: Keep exporting the last inventory of an array which could not be read, flagging it as stale
+func (collector *InventoryCollector) SetScrapeFailed(array string) {
+	collector.scrapeSuccess.WithLabelValues(array).Set(0)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
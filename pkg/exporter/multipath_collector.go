@@ -0,0 +1,63 @@
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MultipathCollector exports the state of the multipath paths of the volumes attached to a node
+type MultipathCollector struct {
+	paths         *prometheus.GaugeVec
+	expectedPaths *prometheus.GaugeVec
+}
+
+const (
+	volumePathsMetric = "seagate_csi_volume_paths"
+	volumePathsHelp   = "How many paths of a volume attached to the node are active, failed or ghost (standby)"
+
+	volumeExpectedPathsMetric = "seagate_csi_volume_expected_paths"
+	volumeExpectedPathsHelp   = "How many paths a volume attached to the node is expected to have for its protocol, 0 when not configured"
+)
+
+func NewMultipathCollector() *MultipathCollector {
+	return &MultipathCollector{
+		paths: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: volumePathsMetric,
+				Help: volumePathsHelp,
+			},
+			[]string{"volume", "protocol", "state"},
+		),
+		expectedPaths: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: volumeExpectedPathsMetric,
+				Help: volumeExpectedPathsHelp,
+			},
+			[]string{"volume", "protocol"},
+		),
+	}
+}
+
+func (collector *MultipathCollector) Describe(ch chan<- *prometheus.Desc) {
+	collector.paths.Describe(ch)
+	collector.expectedPaths.Describe(ch)
+}
+
+func (collector *MultipathCollector) Collect(ch chan<- prometheus.Metric) {
+	collector.paths.Collect(ch)
+	collector.expectedPaths.Collect(ch)
+}
+
+func (collector *MultipathCollector) SetVolumePaths(volume string, protocol string, active int, failed int, ghost int, expected int) {
+	collector.paths.WithLabelValues(volume, protocol, "active").Set(float64(active))
+	collector.paths.WithLabelValues(volume, protocol, "failed").Set(float64(failed))
+	collector.paths.WithLabelValues(volume, protocol, "ghost").Set(float64(ghost))
+	collector.expectedPaths.WithLabelValues(volume, protocol).Set(float64(expected))
+}
+
+// DeleteVolume: Stop exporting the paths of a volume which is no longer attached to the node
+func (collector *MultipathCollector) DeleteVolume(volume string, protocol string) {
+	for _, state := range []string{"active", "failed", "ghost"} {
+		collector.paths.DeleteLabelValues(volume, protocol, state)
+	}
+	collector.expectedPaths.DeleteLabelValues(volume, protocol)
+}
@@ -0,0 +1,32 @@
+package exporter
+
+import (
+	"net/http"
+)
+
+// instrumentedTransport tracks the array API commands sent through an HTTP client
+type instrumentedTransport struct {
+	next      http.RoundTripper
+	collector *Collector
+	command   func(*http.Request) string
+}
+
+// NewInstrumentedTransport: Wrap a transport, nil for the default one, so that the duration of each request is
+// observed under the command returned for it
+func NewInstrumentedTransport(next http.RoundTripper, collector *Collector, command func(*http.Request) string) http.RoundTripper {
+	if instrumented, ok := next.(*instrumentedTransport); ok {
+		// instrumenting a client twice would count its requests twice
+		next = instrumented.next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &instrumentedTransport{next: next, collector: collector, command: command}
+}
+
+func (transport *instrumentedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	done := transport.collector.TrackArrayAPICall(transport.command(request))
+	response, err := transport.next.RoundTrip(request)
+	done(err == nil && response.StatusCode < http.StatusBadRequest)
+	return response, err
+}
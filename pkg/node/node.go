@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/Seagate/csi-lib-iscsi/iscsi"
@@ -28,7 +30,7 @@ type Node struct {
 	semaphore  *semaphore.Weighted
 	runPath    string
 	nodeName   string
-	nodeIP     string
+	nodeID     string
 	nodeServer *grpc.Server
 }
 
@@ -38,34 +40,44 @@ func New() *Node {
 		iscsi.EnableDebugLogging(os.Stderr)
 	}
 
-	envNodeName, _ := os.LookupEnv(common.NodeNameEnvVar)
-	nodeIP, envFound := os.LookupEnv(common.NodeIPEnvVar)
+	envNodeName, envFound := os.LookupEnv(common.NodeNameEnvVar)
 	if !envFound {
-		klog.InfoS("no Node IP found in environment. Using default")
-		nodeIP = "127.0.0.1"
+		klog.InfoS("no Node name found in environment. Using hostname")
+		envNodeName, _ = os.Hostname()
 	}
 	envServicePort, envFound := os.LookupEnv(common.NodeServicePortEnvVar)
 	if !envFound {
 		klog.InfoS("no node service port found in environment. Using default")
 		envServicePort = "978"
 	}
+	runPath, envFound := os.LookupEnv(common.NodeRunPathEnvVar)
+	if !envFound {
+		runPath = fmt.Sprintf("/var/run/%s", common.PluginName)
+	}
 
 	node := &Node{
-		Driver:    common.NewDriver(),
+		Driver:    common.NewDriver(storage.FsCheckMetrics, storage.MultipathMetrics),
 		semaphore: semaphore.NewWeighted(1),
-		runPath:   fmt.Sprintf("/var/run/%s", common.PluginName),
+		runPath:   runPath,
 		nodeName:  envNodeName,
-		nodeIP:    nodeIP,
 	}
 
 	if err := os.MkdirAll(node.runPath, 0755); err != nil {
 		panic(err)
 	}
 
+	// the node name survives IP address and initiator changes, the controller resolves the node service address.
+	// The initiator identity is published in the topology segments instead of the ID: an ID that changes when an
+	// IQN or HBA is replaced would orphan the node's VolumeAttachments, just like the IP based IDs did.
+	node.nodeID = node.nodeName
+	klog.InfoS("node identity", "nodeID", node.nodeID)
+
 	klog.Infof("Node initializing with path: %s", node.runPath)
 
 	requiredBinaries := []string{
 		"blkid",      // command-line utility to locate/print block device attributes
+		"blockdev",   // call block device ioctls from the command line
+		"cryptsetup", // setup LUKS encrypted volumes
 		"findmnt",    // find a filesystem
 		"iscsiadm",   // iscsi administration
 		"mount",      // mount a filesystem
@@ -75,7 +87,6 @@ func New() *Node {
 		"umount",     // unmount file systems
 		"dmsetup",    // device-mapper to remove/clean dm entries
 
-		// "blockdev",    // call block device ioctls from the command line
 		// "lsblk",       // list block devices
 		// "scsi_id",     // retrieve and generate a unique SCSI identifier
 		//	"e2fsck",     // check a Linux ext2/ext3/ext4 file system
@@ -85,9 +96,12 @@ func New() *Node {
 
 	klog.Infof("Checking (%d) binaries", len(requiredBinaries))
 
+	binaries := []string{}
 	for _, binaryName := range requiredBinaries {
 		if err := checkHostBinary(binaryName); err != nil {
 			klog.Warningf("Error locating binary %q", binaryName)
+		} else {
+			binaries = append(binaries, binaryName)
 		}
 	}
 
@@ -114,9 +128,17 @@ func New() *Node {
 	csi.RegisterIdentityServer(node.Server, node)
 	csi.RegisterNodeServer(node.Server, node)
 
+	storage.StartMonitors(node.runPath, expectedPaths())
+
 	// initialize node-controller communication service
-	node.nodeServer = grpc.NewServer()
-	go node_service.ListenAndServe(node.nodeServer, envServicePort)
+	creds, err := node_service.ServerCredentials()
+	if err != nil {
+		// the controller falls back to the initiators published in the node topology
+		klog.ErrorS(err, "node service disabled, unable to load its credentials")
+	} else {
+		node.nodeServer = grpc.NewServer(grpc.Creds(creds))
+		go node_service.ListenAndServe(node.nodeServer, envServicePort, binaries)
+	}
 
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc,
@@ -136,16 +158,92 @@ func New() *Node {
 // NodeGetInfo returns info about the node
 func (node *Node) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
 	return &csi.NodeGetInfoResponse{
-		NodeId:            node.nodeIP,
-		MaxVolumesPerNode: 255,
+		NodeId:            node.nodeID,
+		MaxVolumesPerNode: node.maxVolumesPerNode(),
+		AccessibleTopology: &csi.Topology{
+			Segments: node.topologySegments(),
+		},
 	}, nil
 }
 
+// maxVolumesPerNode: The LUNs a host can use on the array, less the LUNs taken by volumes mapped to the node outside
+// of the driver, or the configured limit when lower. The limit is reported when the node registers, mappings made
+// later are checked by the controller when publishing.
+func (node *Node) maxVolumesPerNode() int64 {
+	// the driver never maps volumes to LUN 0
+	limit := int64(common.MaximumLUN - 1)
+	used, err := storage.UsedLUNs(node.runPath)
+	if err != nil {
+		klog.ErrorS(err, "unable to count the LUNs in use, assuming none")
+	}
+	limit -= int64(used)
+	if value, envFound := os.LookupEnv(common.NodeMaxVolumesEnvVar); envFound {
+		configured, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || configured <= 0 {
+			klog.ErrorS(err, "ignoring invalid maximum number of volumes", "env", common.NodeMaxVolumesEnvVar, "value", value)
+		} else if configured < limit {
+			limit = configured
+		}
+	}
+	klog.InfoS("maximum number of volumes", "limit", limit, "usedLUNs", used)
+	return limit
+}
+
+// expectedPaths: The number of multipath paths the volumes of each protocol are expected to have, volumes of protocols
+// without a count are only reported as degraded once they have no active path left
+func expectedPaths() map[string]int {
+	paths := map[string]int{}
+	for _, entry := range strings.Split(os.Getenv(common.NodeExpectedPathsEnvVar), ",") {
+		protocol, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || count < 0 {
+			klog.ErrorS(err, "ignoring invalid number of expected paths", "env", common.NodeExpectedPathsEnvVar, "protocol", protocol, "value", value)
+			continue
+		}
+		paths[strings.TrimSpace(protocol)] = count
+	}
+	return paths
+}
+
+// topologySegments: Publish the storage protocols this node can use to reach the arrays, along with the initiators
+// the controller maps volumes to, so that volumes are only provisioned where they can be attached
+func (node *Node) topologySegments() map[string]string {
+	segments := map[string]string{
+		common.TopologyNodeIDKey: common.GetTopologyCompliantNodeID(node.nodeID),
+	}
+	for _, protocol := range []string{common.StorageProtocolISCSI, common.StorageProtocolFC, common.StorageProtocolSAS} {
+		initiators, err := storage.GetInitiators(protocol)
+		if err != nil || len(initiators) == 0 {
+			klog.V(2).InfoS("storage protocol not available on this node", "protocol", protocol, "err", err)
+			continue
+		}
+		segments[common.GetTopologyProtocolKey(protocol)] = "true"
+		// the controller falls back to the node service when fewer initiators than counted are in the topology
+		segments[common.TopologyInitiatorPrefix+"/"+common.GetTopologyInitiatorCountLabel(protocol)] = strconv.Itoa(len(initiators))
+
+		label := common.GetTopologyInitiatorLabel(protocol)
+		for i, initiator := range initiators {
+			value, ok := common.GetTopologyCompliantInitiator(initiator)
+			if !ok {
+				klog.InfoS("initiator cannot be published in node topology", "protocol", protocol, "initiator", initiator)
+				continue
+			}
+			segments[fmt.Sprintf("%s/%s-%d", common.TopologyInitiatorPrefix, label, i)] = value
+		}
+	}
+	klog.V(2).InfoS("node topology", "segments", segments)
+	return segments
+}
+
 // NodeGetCapabilities returns the supported capabilities of the node server
 func (node *Node) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
 	var csc []*csi.NodeServiceCapability
 	cl := []csi.NodeServiceCapability_RPC_Type{
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_VOLUME_MOUNT_GROUP,
 	}
 
 	for _, cap := range cl {
@@ -206,8 +304,20 @@ func (node *Node) NodePublishVolume(ctx context.Context, req *csi.NodePublishVol
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// Encrypted volumes are formatted and mounted through their LUKS mapping
+	encrypted, err := storage.IsEncrypted(req.GetVolumeContext())
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		path, err = storage.OpenEncryptedDevice(path, req.GetVolumeId(), req.GetSecrets())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if req.GetVolumeCapability().GetMount() != nil {
-		err = storage.MountFilesystem(req, path)
+		err = storage.MountFilesystem(req, path, node.runPath)
 	} else if req.GetVolumeCapability().GetBlock() != nil {
 		err = storage.MountDevice(req, path)
 	}
@@ -252,6 +362,10 @@ func (node *Node) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublis
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	// the connector is only removed once the volume is detached, not when it is still in use by another target
+	if _, err := os.Stat(config["connectorInfoPath"]); os.IsNotExist(err) {
+		storage.RemoveFsCheckRecord(node.runPath, req.GetVolumeId())
+	}
 
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
@@ -308,13 +422,16 @@ func (node *Node) Probe(ctx context.Context, req *csi.ProbeRequest) (*csi.ProbeR
 
 // getConnectorInfoPath
 func (node *Node) getConnectorInfoPath(storageProtocol, volumeID string) string {
-	return fmt.Sprintf("%s/%s-%s.json", node.runPath, storageProtocol, volumeID)
+	return storage.ConnectorInfoPath(node.runPath, storageProtocol, volumeID)
 }
 
 // Graceful shutdown of the node-controller RPC server
 func (node *Node) Stop() {
 	klog.V(3).InfoS("Node graceful shutdown..")
-	node.nodeServer.GracefulStop()
+	if node.nodeServer != nil {
+		node.nodeServer.GracefulStop()
+	}
+	storage.StopDeviceWatcher()
 	node.Driver.Stop()
 }
 
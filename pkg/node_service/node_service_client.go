@@ -2,13 +2,13 @@ package node_service
 
 import (
 	"context"
+	"net"
 	"os"
 	"time"
 
 	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
 	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"k8s.io/klog/v2"
 )
 
@@ -18,8 +18,13 @@ func InitializeClient(nodeAddress string) (conn *grpc.ClientConn, err error) {
 		port = "978"
 		klog.InfoS("no node service port found in environment. using default", "port", port)
 	}
-	nodeServiceAddr := nodeAddress + ":" + port
-	conn, err = grpc.Dial(nodeServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := ClientCredentials()
+	if err != nil {
+		klog.ErrorS(err, "Error loading node service credentials")
+		return
+	}
+	nodeServiceAddr := net.JoinHostPort(nodeAddress, port)
+	conn, err = grpc.Dial(nodeServiceAddr, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		klog.ErrorS(err, "Error connecting to node service", "node ip", nodeAddress, "port", port)
 		return
@@ -52,3 +57,46 @@ func NotifyUnmap(ctx context.Context, conn *grpc.ClientConn, volumeWWN string) (
 	}
 	return
 }
+
+// Connect to the node_service gRPC server at the given address and retrieve the node plugin version and capabilities
+func GetNodeInfo(ctx context.Context, conn *grpc.ClientConn) (*pb.NodeInfo, error) {
+	client := pb.NewNodeServiceClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	info, err := client.GetNodeInfo(ctx, &pb.NodeInfoRequest{ControllerVersion: common.Version, ApiVersion: common.NodeServiceAPIVersion})
+	if err != nil {
+		klog.ErrorS(err, "Error during GetNodeInfo")
+		return nil, err
+	}
+	return info, nil
+}
+
+// Connect to the node_service gRPC server at the given address and probe for a newly mapped LUN
+func RescanLUN(ctx context.Context, conn *grpc.ClientConn, reqType pb.InitiatorType, volumeWWN string, lun int32) ([]string, error) {
+	client := pb.NewNodeServiceClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	targets, err := client.RescanLUN(ctx, &pb.MappedVolume{Type: reqType, VolumeName: volumeWWN, Lun: lun})
+	if err != nil {
+		klog.ErrorS(err, "Error during RescanLUN", "volumeName", volumeWWN, "lun", lun)
+		return nil, err
+	}
+	return targets.Targets, nil
+}
+
+// Connect to the node_service gRPC server at the given address and receive its device health events until the context
+// is canceled or the stream breaks
+func WatchHealth(ctx context.Context, conn *grpc.ClientConn, volumeWWNs []string, handler func(*pb.HealthEvent)) error {
+	client := pb.NewNodeServiceClient(conn)
+	stream, err := client.WatchHealth(ctx, &pb.HealthRequest{Wwns: volumeWWNs})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		handler(event)
+	}
+}
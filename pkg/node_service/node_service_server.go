@@ -3,7 +3,9 @@ package node_service
 import (
 	"context"
 	"net"
+	"slices"
 
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
 	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
 	"github.com/Seagate/seagate-exos-x-csi/pkg/storage"
 	"google.golang.org/grpc"
@@ -12,6 +14,7 @@ import (
 
 type server struct {
 	pb.UnimplementedNodeServiceServer
+	binaries []string
 }
 
 // Retrieve initiator addresses from the node
@@ -20,14 +23,14 @@ func (s *server) GetInitiators(ctx context.Context, in *pb.InitiatorRequest) (*p
 	var err error
 	switch in.GetType() {
 	case pb.InitiatorType_FC:
-		initiators, err = storage.GetFCInitiators()
+		initiators, err = storage.GetInitiators(common.StorageProtocolFC)
 	case pb.InitiatorType_SAS:
-		initiators, err = storage.GetSASInitiators()
+		initiators, err = storage.GetInitiators(common.StorageProtocolSAS)
 	case pb.InitiatorType_ISCSI:
-		initiators, err = storage.GetISCSIInitiators()
+		initiators, err = storage.GetInitiators(common.StorageProtocolISCSI)
 	case pb.InitiatorType_UNSPECIFIED:
 		klog.InfoS("Unspecified initiator type in initiator request, defaulting to iSCSI")
-		initiators, err = storage.GetISCSIInitiators()
+		initiators, err = storage.GetInitiators(common.StorageProtocolISCSI)
 	}
 	if err != nil {
 		return nil, err
@@ -43,12 +46,121 @@ func (s *server) NotifyUnmap(ctx context.Context, in *pb.UnmappedVolume) (*pb.Ac
 	return &pb.Ack{Ack: 1}, nil
 }
 
-func ListenAndServe(s *grpc.Server, port string) {
+// Report the node plugin version and what the node supports, so that the controller can tell whether it is compatible
+func (s *server) GetNodeInfo(ctx context.Context, in *pb.NodeInfoRequest) (*pb.NodeInfo, error) {
+	klog.V(2).InfoS("node info requested", "controllerVersion", in.GetControllerVersion(), "apiVersion", in.GetApiVersion())
+	protocols := []string{}
+	for _, protocol := range []string{common.StorageProtocolISCSI, common.StorageProtocolFC, common.StorageProtocolSAS} {
+		if initiators, err := storage.GetInitiators(protocol); err == nil && len(initiators) > 0 {
+			protocols = append(protocols, protocol)
+		}
+	}
+	rpcs := []string{}
+	for _, method := range pb.NodeService_ServiceDesc.Methods {
+		rpcs = append(rpcs, method.MethodName)
+	}
+	for _, stream := range pb.NodeService_ServiceDesc.Streams {
+		rpcs = append(rpcs, stream.StreamName)
+	}
+	return &pb.NodeInfo{
+		Version:    common.Version,
+		ApiVersion: common.NodeServiceAPIVersion,
+		Protocols:  protocols,
+		Binaries:   s.binaries,
+		Rpcs:       rpcs,
+	}, nil
+}
+
+// Probe for a LUN the controller has just mapped, so that the volume is found without waiting for a periodic rescan
+func (s *server) RescanLUN(ctx context.Context, in *pb.MappedVolume) (*pb.ScannedTargets, error) {
+	protocol := common.StorageProtocolISCSI
+	switch in.GetType() {
+	case pb.InitiatorType_FC:
+		protocol = common.StorageProtocolFC
+	case pb.InitiatorType_SAS:
+		protocol = common.StorageProtocolSAS
+	}
+	klog.V(2).InfoS("rescan requested", "volumeName", in.GetVolumeName(), "protocol", protocol, "lun", in.GetLun())
+	targets, err := storage.RescanLUN(protocol, int(in.GetLun()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ScannedTargets{Targets: targets}, nil
+}
+
+// Stream the device health events of the node, for the given volume WWNs or for every volume when none is given
+func (s *server) WatchHealth(in *pb.HealthRequest, stream pb.NodeService_WatchHealthServer) error {
+	klog.V(2).InfoS("health events requested", "wwns", in.GetWwns())
+	events, unsubscribe := storage.SubscribeHealthEvents()
+	defer unsubscribe()
+	// start with the current state, the events that led to it may have been sent to a previous stream or none at all
+	snapshot, err := storage.HealthSnapshot()
+	if err != nil {
+		klog.ErrorS(err, "unable to take a snapshot of the device health")
+	}
+	for _, event := range snapshot {
+		if len(in.GetWwns()) > 0 && !slices.Contains(in.GetWwns(), event.WWN) {
+			continue
+		}
+		if err := stream.Send(healthEventMessage(event)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if len(in.GetWwns()) > 0 && !slices.Contains(in.GetWwns(), event.WWN) {
+				continue
+			}
+			if err := stream.Send(healthEventMessage(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// healthEventMessage: Convert a device health event to its node service message
+func healthEventMessage(event storage.HealthEvent) *pb.HealthEvent {
+	eventType := pb.HealthEventType_HEALTH_EVENT_UNSPECIFIED
+	switch event.Type {
+	case storage.HealthPathFailed:
+		eventType = pb.HealthEventType_PATH_FAILED
+	case storage.HealthPathRestored:
+		eventType = pb.HealthEventType_PATH_RESTORED
+	case storage.HealthReadOnlyRemount:
+		eventType = pb.HealthEventType_READ_ONLY_REMOUNT
+	case storage.HealthSCSIError:
+		eventType = pb.HealthEventType_SCSI_ERROR
+	case storage.HealthPathSnapshot:
+		eventType = pb.HealthEventType_PATH_SNAPSHOT
+	}
+	severity := pb.Severity_SEVERITY_INFO
+	switch event.Severity {
+	case storage.HealthSeverityWarning:
+		severity = pb.Severity_SEVERITY_WARNING
+	case storage.HealthSeverityError:
+		severity = pb.Severity_SEVERITY_ERROR
+	}
+	return &pb.HealthEvent{
+		Type:        eventType,
+		Severity:    severity,
+		Wwn:         event.WWN,
+		Path:        event.Path,
+		Message:     event.Message,
+		Timestamp:   event.Timestamp.UnixNano(),
+		FailedPaths: event.FailedPaths,
+	}
+}
+
+// ListenAndServe: Serve the node service, binaries being the host binaries found on the node
+func ListenAndServe(s *grpc.Server, port string, binaries []string) {
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		klog.ErrorS(err, "Node Service gRPC server failed to listen")
 	}
-	pb.RegisterNodeServiceServer(s, &server{})
+	pb.RegisterNodeServiceServer(s, &server{binaries: binaries})
 	klog.V(0).InfoS("Node Service gRPC server listening", "address", lis.Addr())
 	s.Serve(lis)
 }
@@ -72,6 +72,113 @@ func (InitiatorType) EnumDescriptor() ([]byte, []int) {
 	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{0}
 }
 
+type HealthEventType int32
+
+const (
+	HealthEventType_HEALTH_EVENT_UNSPECIFIED HealthEventType = 0
+	HealthEventType_PATH_FAILED              HealthEventType = 1
+	HealthEventType_PATH_RESTORED            HealthEventType = 2
+	HealthEventType_READ_ONLY_REMOUNT        HealthEventType = 3
+	HealthEventType_SCSI_ERROR               HealthEventType = 4
+	HealthEventType_PATH_SNAPSHOT            HealthEventType = 5
+)
+
+// Enum value maps for HealthEventType.
+var (
+	HealthEventType_name = map[int32]string{
+		0: "HEALTH_EVENT_UNSPECIFIED",
+		1: "PATH_FAILED",
+		2: "PATH_RESTORED",
+		3: "READ_ONLY_REMOUNT",
+		4: "SCSI_ERROR",
+		5: "PATH_SNAPSHOT",
+	}
+	HealthEventType_value = map[string]int32{
+		"HEALTH_EVENT_UNSPECIFIED": 0,
+		"PATH_FAILED":              1,
+		"PATH_RESTORED":            2,
+		"READ_ONLY_REMOUNT":        3,
+		"SCSI_ERROR":               4,
+		"PATH_SNAPSHOT":            5,
+	}
+)
+
+func (x HealthEventType) Enum() *HealthEventType {
+	p := new(HealthEventType)
+	*p = x
+	return p
+}
+
+func (x HealthEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes[1].Descriptor()
+}
+
+func (HealthEventType) Type() protoreflect.EnumType {
+	return &file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes[1]
+}
+
+func (x HealthEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthEventType.Descriptor instead.
+func (HealthEventType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{1}
+}
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_INFO    Severity = 0
+	Severity_SEVERITY_WARNING Severity = 1
+	Severity_SEVERITY_ERROR   Severity = 2
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_INFO",
+		1: "SEVERITY_WARNING",
+		2: "SEVERITY_ERROR",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_INFO":    0,
+		"SEVERITY_WARNING": 1,
+		"SEVERITY_ERROR":   2,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes[2].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes[2]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{2}
+}
+
 type InitiatorRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -260,6 +367,392 @@ func (x *Ack) GetAck() int32 {
 	return 0
 }
 
+type NodeInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ControllerVersion string `protobuf:"bytes,1,opt,name=controllerVersion,proto3" json:"controllerVersion,omitempty"`
+	ApiVersion        int32  `protobuf:"varint,2,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+}
+
+func (x *NodeInfoRequest) Reset() {
+	*x = NodeInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfoRequest) ProtoMessage() {}
+
+func (x *NodeInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfoRequest.ProtoReflect.Descriptor instead.
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NodeInfoRequest) GetControllerVersion() string {
+	if x != nil {
+		return x.ControllerVersion
+	}
+	return ""
+}
+
+func (x *NodeInfoRequest) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
+type NodeInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	ApiVersion int32    `protobuf:"varint,2,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	Protocols  []string `protobuf:"bytes,3,rep,name=protocols,proto3" json:"protocols,omitempty"`
+	Binaries   []string `protobuf:"bytes,4,rep,name=binaries,proto3" json:"binaries,omitempty"`
+	Rpcs       []string `protobuf:"bytes,5,rep,name=rpcs,proto3" json:"rpcs,omitempty"`
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfo) ProtoMessage() {}
+
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NodeInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetApiVersion() int32 {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetProtocols() []string {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+func (x *NodeInfo) GetBinaries() []string {
+	if x != nil {
+		return x.Binaries
+	}
+	return nil
+}
+
+func (x *NodeInfo) GetRpcs() []string {
+	if x != nil {
+		return x.Rpcs
+	}
+	return nil
+}
+
+type MappedVolume struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       InitiatorType `protobuf:"varint,1,opt,name=type,proto3,enum=node_service.InitiatorType" json:"type,omitempty"`
+	VolumeName string        `protobuf:"bytes,2,opt,name=volumeName,proto3" json:"volumeName,omitempty"`
+	Lun        int32         `protobuf:"varint,3,opt,name=lun,proto3" json:"lun,omitempty"`
+}
+
+func (x *MappedVolume) Reset() {
+	*x = MappedVolume{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MappedVolume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MappedVolume) ProtoMessage() {}
+
+func (x *MappedVolume) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MappedVolume.ProtoReflect.Descriptor instead.
+func (*MappedVolume) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MappedVolume) GetType() InitiatorType {
+	if x != nil {
+		return x.Type
+	}
+	return InitiatorType_UNSPECIFIED
+}
+
+func (x *MappedVolume) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+func (x *MappedVolume) GetLun() int32 {
+	if x != nil {
+		return x.Lun
+	}
+	return 0
+}
+
+type ScannedTargets struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Targets []string `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *ScannedTargets) Reset() {
+	*x = ScannedTargets{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScannedTargets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScannedTargets) ProtoMessage() {}
+
+func (x *ScannedTargets) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScannedTargets.ProtoReflect.Descriptor instead.
+func (*ScannedTargets) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScannedTargets) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wwns []string `protobuf:"bytes,1,rep,name=wwns,proto3" json:"wwns,omitempty"`
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HealthRequest) GetWwns() []string {
+	if x != nil {
+		return x.Wwns
+	}
+	return nil
+}
+
+type HealthEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        HealthEventType `protobuf:"varint,1,opt,name=type,proto3,enum=node_service.HealthEventType" json:"type,omitempty"`
+	Severity    Severity        `protobuf:"varint,2,opt,name=severity,proto3,enum=node_service.Severity" json:"severity,omitempty"`
+	Wwn         string          `protobuf:"bytes,3,opt,name=wwn,proto3" json:"wwn,omitempty"`
+	Path        string          `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Message     string          `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp   int64           `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	FailedPaths []string        `protobuf:"bytes,7,rep,name=failedPaths,proto3" json:"failedPaths,omitempty"`
+}
+
+func (x *HealthEvent) Reset() {
+	*x = HealthEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthEvent) ProtoMessage() {}
+
+func (x *HealthEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthEvent.ProtoReflect.Descriptor instead.
+func (*HealthEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HealthEvent) GetType() HealthEventType {
+	if x != nil {
+		return x.Type
+	}
+	return HealthEventType_HEALTH_EVENT_UNSPECIFIED
+}
+
+func (x *HealthEvent) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_INFO
+}
+
+func (x *HealthEvent) GetWwn() string {
+	if x != nil {
+		return x.Wwn
+	}
+	return ""
+}
+
+func (x *HealthEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *HealthEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HealthEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HealthEvent) GetFailedPaths() []string {
+	if x != nil {
+		return x.FailedPaths
+	}
+	return nil
+}
+
 var File_pkg_node_service_node_servicepb_node_rpc_proto protoreflect.FileDescriptor
 
 var file_pkg_node_service_node_servicepb_node_rpc_proto_rawDesc = []byte{
@@ -278,27 +771,97 @@ var file_pkg_node_service_node_servicepb_node_rpc_proto_rawDesc = []byte{
 	0x75, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4e,
 	0x61, 0x6d, 0x65, 0x22, 0x17, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x63,
-	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x2a, 0x3c, 0x0a, 0x0d,
-	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a,
-	0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x06,
-	0x0a, 0x02, 0x46, 0x43, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x41, 0x53, 0x10, 0x02, 0x12,
-	0x09, 0x0a, 0x05, 0x49, 0x53, 0x43, 0x53, 0x49, 0x10, 0x03, 0x32, 0x9c, 0x01, 0x0a, 0x0b, 0x4e,
-	0x6f, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0d, 0x47, 0x65,
-	0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69,
-	0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69,
-	0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0b, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x79, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x12, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x56, 0x6f,
-	0x6c, 0x75, 0x6d, 0x65, 0x1a, 0x11, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x42, 0x57, 0x5a, 0x55, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x65, 0x61, 0x67, 0x61, 0x74, 0x65, 0x2f,
-	0x73, 0x65, 0x61, 0x67, 0x61, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x6f, 0x73, 0x2d, 0x78, 0x2d, 0x63,
-	0x73, 0x69, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x75, 0x66, 0x66,
-	0x65, 0x72, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x22, 0x5f, 0x0a, 0x0f,
+	0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2c, 0x0a, 0x11, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x63, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x92, 0x01,
+	0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x70, 0x63, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x72, 0x70,
+	0x63, 0x73, 0x22, 0x71, 0x0a, 0x0c, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x56, 0x6f, 0x6c, 0x75,
+	0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x75, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x6c, 0x75, 0x6e, 0x22, 0x2a, 0x0a, 0x0e, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x22, 0x23, 0x0a, 0x0d, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x77, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x77, 0x77, 0x6e, 0x73, 0x22, 0xf4, 0x01, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x31, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72,
+	0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x77, 0x77, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x77, 0x77, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x66,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x2a, 0x3c, 0x0a,
+	0x0d, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f,
+	0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x06, 0x0a, 0x02, 0x46, 0x43, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x41, 0x53, 0x10, 0x02,
+	0x12, 0x09, 0x0a, 0x05, 0x49, 0x53, 0x43, 0x53, 0x49, 0x10, 0x03, 0x2a, 0x8d, 0x01, 0x0a, 0x0f,
+	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1c, 0x0a, 0x18, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a,
+	0x0b, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x11,
+	0x0a, 0x0d, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x52, 0x45, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x5f, 0x52,
+	0x45, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x43, 0x53, 0x49,
+	0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x41, 0x54, 0x48,
+	0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x05, 0x2a, 0x47, 0x0a, 0x08, 0x53,
+	0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x56, 0x45, 0x52,
+	0x49, 0x54, 0x59, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45,
+	0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41, 0x52, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x12, 0x0a, 0x0e, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x02, 0x32, 0xf8, 0x02, 0x0a, 0x0b, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22,
+	0x00, 0x12, 0x40, 0x0a, 0x0b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x55, 0x6e, 0x6d, 0x61, 0x70,
+	0x12, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x55, 0x6e, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x1a, 0x11,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x63,
+	0x6b, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x09, 0x52,
+	0x65, 0x73, 0x63, 0x61, 0x6e, 0x4c, 0x55, 0x4e, 0x12, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x56, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61,
+	0x6c, 0x74, 0x68, 0x12, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x42,
+	0x57, 0x5a, 0x55, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x65,
+	0x61, 0x67, 0x61, 0x74, 0x65, 0x2f, 0x73, 0x65, 0x61, 0x67, 0x61, 0x74, 0x65, 0x2d, 0x65, 0x78,
+	0x6f, 0x73, 0x2d, 0x78, 0x2d, 0x63, 0x73, 0x69, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -313,26 +876,43 @@ func file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescGZIP() []byte {
 	return file_pkg_node_service_node_servicepb_node_rpc_proto_rawDescData
 }
 
-var file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_node_service_node_servicepb_node_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_pkg_node_service_node_servicepb_node_rpc_proto_goTypes = []interface{}{
 	(InitiatorType)(0),       // 0: node_service.InitiatorType
-	(*InitiatorRequest)(nil), // 1: node_service.InitiatorRequest
-	(*Initiators)(nil),       // 2: node_service.Initiators
-	(*UnmappedVolume)(nil),   // 3: node_service.UnmappedVolume
-	(*Ack)(nil),              // 4: node_service.Ack
+	(HealthEventType)(0),     // 1: node_service.HealthEventType
+	(Severity)(0),            // 2: node_service.Severity
+	(*InitiatorRequest)(nil), // 3: node_service.InitiatorRequest
+	(*Initiators)(nil),       // 4: node_service.Initiators
+	(*UnmappedVolume)(nil),   // 5: node_service.UnmappedVolume
+	(*Ack)(nil),              // 6: node_service.Ack
+	(*NodeInfoRequest)(nil),  // 7: node_service.NodeInfoRequest
+	(*NodeInfo)(nil),         // 8: node_service.NodeInfo
+	(*MappedVolume)(nil),     // 9: node_service.MappedVolume
+	(*ScannedTargets)(nil),   // 10: node_service.ScannedTargets
+	(*HealthRequest)(nil),    // 11: node_service.HealthRequest
+	(*HealthEvent)(nil),      // 12: node_service.HealthEvent
 }
 var file_pkg_node_service_node_servicepb_node_rpc_proto_depIdxs = []int32{
-	0, // 0: node_service.InitiatorRequest.type:type_name -> node_service.InitiatorType
-	1, // 1: node_service.NodeService.GetInitiators:input_type -> node_service.InitiatorRequest
-	3, // 2: node_service.NodeService.NotifyUnmap:input_type -> node_service.UnmappedVolume
-	2, // 3: node_service.NodeService.GetInitiators:output_type -> node_service.Initiators
-	4, // 4: node_service.NodeService.NotifyUnmap:output_type -> node_service.Ack
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: node_service.InitiatorRequest.type:type_name -> node_service.InitiatorType
+	0,  // 1: node_service.MappedVolume.type:type_name -> node_service.InitiatorType
+	1,  // 2: node_service.HealthEvent.type:type_name -> node_service.HealthEventType
+	2,  // 3: node_service.HealthEvent.severity:type_name -> node_service.Severity
+	3,  // 4: node_service.NodeService.GetInitiators:input_type -> node_service.InitiatorRequest
+	5,  // 5: node_service.NodeService.NotifyUnmap:input_type -> node_service.UnmappedVolume
+	7,  // 6: node_service.NodeService.GetNodeInfo:input_type -> node_service.NodeInfoRequest
+	9,  // 7: node_service.NodeService.RescanLUN:input_type -> node_service.MappedVolume
+	11, // 8: node_service.NodeService.WatchHealth:input_type -> node_service.HealthRequest
+	4,  // 9: node_service.NodeService.GetInitiators:output_type -> node_service.Initiators
+	6,  // 10: node_service.NodeService.NotifyUnmap:output_type -> node_service.Ack
+	8,  // 11: node_service.NodeService.GetNodeInfo:output_type -> node_service.NodeInfo
+	10, // 12: node_service.NodeService.RescanLUN:output_type -> node_service.ScannedTargets
+	12, // 13: node_service.NodeService.WatchHealth:output_type -> node_service.HealthEvent
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_pkg_node_service_node_servicepb_node_rpc_proto_init() }
@@ -389,14 +969,86 @@ func file_pkg_node_service_node_servicepb_node_rpc_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MappedVolume); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScannedTargets); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_node_service_node_servicepb_node_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_node_service_node_servicepb_node_rpc_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   4,
+			NumEnums:      3,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
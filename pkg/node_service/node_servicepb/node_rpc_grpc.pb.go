@@ -24,6 +24,9 @@ const _ = grpc.SupportPackageIsVersion7
 type NodeServiceClient interface {
 	GetInitiators(ctx context.Context, in *InitiatorRequest, opts ...grpc.CallOption) (*Initiators, error)
 	NotifyUnmap(ctx context.Context, in *UnmappedVolume, opts ...grpc.CallOption) (*Ack, error)
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	RescanLUN(ctx context.Context, in *MappedVolume, opts ...grpc.CallOption) (*ScannedTargets, error)
+	WatchHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (NodeService_WatchHealthClient, error)
 }
 
 type nodeServiceClient struct {
@@ -52,12 +55,65 @@ func (c *nodeServiceClient) NotifyUnmap(ctx context.Context, in *UnmappedVolume,
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := c.cc.Invoke(ctx, "/node_service.NodeService/GetNodeInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RescanLUN(ctx context.Context, in *MappedVolume, opts ...grpc.CallOption) (*ScannedTargets, error) {
+	out := new(ScannedTargets)
+	err := c.cc.Invoke(ctx, "/node_service.NodeService/RescanLUN", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) WatchHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (NodeService_WatchHealthClient, error) {
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[0], "/node_service.NodeService/WatchHealth", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeServiceWatchHealthClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NodeService_WatchHealthClient interface {
+	Recv() (*HealthEvent, error)
+	grpc.ClientStream
+}
+
+type nodeServiceWatchHealthClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeServiceWatchHealthClient) Recv() (*HealthEvent, error) {
+	m := new(HealthEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // NodeServiceServer is the server API for NodeService service.
 // All implementations must embed UnimplementedNodeServiceServer
 // for forward compatibility
 type NodeServiceServer interface {
 	GetInitiators(context.Context, *InitiatorRequest) (*Initiators, error)
 	NotifyUnmap(context.Context, *UnmappedVolume) (*Ack, error)
+	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
+	RescanLUN(context.Context, *MappedVolume) (*ScannedTargets, error)
+	WatchHealth(*HealthRequest, NodeService_WatchHealthServer) error
 	mustEmbedUnimplementedNodeServiceServer()
 }
 
@@ -71,6 +127,15 @@ func (UnimplementedNodeServiceServer) GetInitiators(context.Context, *InitiatorR
 func (UnimplementedNodeServiceServer) NotifyUnmap(context.Context, *UnmappedVolume) (*Ack, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyUnmap not implemented")
 }
+func (UnimplementedNodeServiceServer) GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (UnimplementedNodeServiceServer) RescanLUN(context.Context, *MappedVolume) (*ScannedTargets, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescanLUN not implemented")
+}
+func (UnimplementedNodeServiceServer) WatchHealth(*HealthRequest, NodeService_WatchHealthServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchHealth not implemented")
+}
 func (UnimplementedNodeServiceServer) mustEmbedUnimplementedNodeServiceServer() {}
 
 // UnsafeNodeServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +185,63 @@ func _NodeService_NotifyUnmap_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node_service.NodeService/GetNodeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RescanLUN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MappedVolume)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RescanLUN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node_service.NodeService/RescanLUN",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RescanLUN(ctx, req.(*MappedVolume))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_WatchHealth_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HealthRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).WatchHealth(m, &nodeServiceWatchHealthServer{stream})
+}
+
+type NodeService_WatchHealthServer interface {
+	Send(*HealthEvent) error
+	grpc.ServerStream
+}
+
+type nodeServiceWatchHealthServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeServiceWatchHealthServer) Send(m *HealthEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // NodeService_ServiceDesc is the grpc.ServiceDesc for NodeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,7 +257,21 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NotifyUnmap",
 			Handler:    _NodeService_NotifyUnmap_Handler,
 		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _NodeService_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "RescanLUN",
+			Handler:    _NodeService_RescanLUN_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchHealth",
+			Handler:       _NodeService_WatchHealth_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/node_service/node_servicepb/node_rpc.proto",
 }
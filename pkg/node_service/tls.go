@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package node_service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+)
+
+// certificateReloader: a certificate, its key and the CA bundle the peer certificates must be signed by, read from
+// files and read again when one of them changes so that rotated certificates are used without a restart
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.Mutex
+	modTimes [3]time.Time
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+}
+
+// newCertificateReloader: Load the certificate files given by the node service TLS environment variables
+func newCertificateReloader() (*certificateReloader, error) {
+	reloader := &certificateReloader{
+		certFile: os.Getenv(common.NodeServiceTLSCertEnvVar),
+		keyFile:  os.Getenv(common.NodeServiceTLSKeyEnvVar),
+		caFile:   os.Getenv(common.NodeServiceTLSCAEnvVar),
+	}
+	if reloader.certFile == "" || reloader.keyFile == "" || reloader.caFile == "" {
+		return nil, fmt.Errorf("node service TLS requires %s, %s and %s, or %s=true to disable it in tests",
+			common.NodeServiceTLSCertEnvVar, common.NodeServiceTLSKeyEnvVar, common.NodeServiceTLSCAEnvVar, common.NodeServiceInsecureEnvVar)
+	}
+	if _, _, err := reloader.load(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// load: Return the current certificate and CA pool, reading the files again if one of them was modified
+func (reloader *certificateReloader) load() (*tls.Certificate, *x509.CertPool, error) {
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+
+	modTimes := [3]time.Time{}
+	for i, file := range []string{reloader.certFile, reloader.keyFile, reloader.caFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if reloader.cert != nil && modTimes == reloader.modTimes {
+		return reloader.cert, reloader.pool, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := os.ReadFile(reloader.caFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, nil, fmt.Errorf("no certificate found in %s", reloader.caFile)
+	}
+
+	klog.InfoS("loaded node service certificates", "cert", reloader.certFile, "ca", reloader.caFile)
+	reloader.cert, reloader.pool, reloader.modTimes = &cert, pool, modTimes
+	return reloader.cert, reloader.pool, nil
+}
+
+// verifyPeer: Check that the peer certificate chain is signed by the current CA and names the expected peer
+func (reloader *certificateReloader) verifyPeer(rawCerts [][]byte, name string, usage x509.ExtKeyUsage) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificate")
+	}
+	_, pool, err := reloader.load()
+	if err != nil {
+		return err
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{Roots: pool, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{usage}}); err != nil {
+		return err
+	}
+	if certs[0].Subject.CommonName != name && certs[0].VerifyHostname(name) != nil {
+		return fmt.Errorf("peer certificate is not issued to %s", name)
+	}
+	return nil
+}
+
+// isInsecure: Return true when the node service runs without TLS, which is only meant for tests
+func isInsecure() bool {
+	return os.Getenv(common.NodeServiceInsecureEnvVar) == "true"
+}
+
+// ServerCredentials: Return the transport credentials of the node service, which only accepts the controller
+func ServerCredentials() (credentials.TransportCredentials, error) {
+	if isInsecure() {
+		klog.InfoS("node service TLS is disabled, anyone reaching the node can use the node service", "env", common.NodeServiceInsecureEnvVar)
+		return insecure.NewCredentials(), nil
+	}
+	reloader, err := newCertificateReloader()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// the chain is verified against the reloaded CA pool below
+		ClientAuth: tls.RequireAnyClientCert,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _, err := reloader.load()
+			return cert, err
+		},
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return reloader.verifyPeer(rawCerts, common.NodeServiceClientName, x509.ExtKeyUsageClientAuth)
+		},
+	}), nil
+}
+
+// ClientCredentials: Return the transport credentials the controller uses to reach the node services
+func ClientCredentials() (credentials.TransportCredentials, error) {
+	if isInsecure() {
+		return insecure.NewCredentials(), nil
+	}
+	reloader, err := newCertificateReloader()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// nodes are reached by address but share a certificate, which is verified against the reloaded CA pool below
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _, err := reloader.load()
+			return cert, err
+		},
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return reloader.verifyPeer(rawCerts, common.NodeServiceServerName, x509.ExtKeyUsageServerAuth)
+		},
+	}), nil
+}
+
+// plaintextHandshakeError: what the TLS handshake of the controller fails with when the node service answers in plain
+// text, as node plugins released before the node service was secured do
+const plaintextHandshakeError = "first record does not look like a TLS handshake"
+
+// IsPlaintextPeer: Return true when an RPC failed because the node service does not use TLS
+func IsPlaintextPeer(err error) bool {
+	return err != nil && strings.Contains(err.Error(), plaintextHandshakeError)
+}
@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package node_service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	pb "github.com/Seagate/seagate-exos-x-csi/pkg/node_service/node_servicepb"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// testCA: a certificate authority issuing node service certificates into a directory
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(g *WithT) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(HaveOccurred())
+	return &testCA{cert: cert, key: key}
+}
+
+// issue: write a certificate for name, its key and the CA bundle into dir
+func (ca *testCA) issue(g *WithT, dir string, name string, usage x509.ExtKeyUsage) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	g.Expect(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	write := func(file string, blockType string, bytes []byte) {
+		g.Expect(os.WriteFile(filepath.Join(dir, file), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0600)).To(Succeed())
+	}
+	write("tls.crt", "CERTIFICATE", der)
+	write("tls.key", "EC PRIVATE KEY", keyDer)
+	write("ca.crt", "CERTIFICATE", ca.cert.Raw)
+}
+
+// useCertificates: point the node service TLS environment variables to the files issued into dir
+func useCertificates(t *testing.T, dir string) {
+	t.Setenv(common.NodeServiceTLSCertEnvVar, filepath.Join(dir, "tls.crt"))
+	t.Setenv(common.NodeServiceTLSKeyEnvVar, filepath.Join(dir, "tls.key"))
+	t.Setenv(common.NodeServiceTLSCAEnvVar, filepath.Join(dir, "ca.crt"))
+}
+
+func notifyUnmap(address string, creds credentials.TransportCredentials) error {
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewNodeServiceClient(conn).NotifyUnmap(ctx, &pb.UnmappedVolume{VolumeName: "600c0ff0000000000000000000000001"})
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	g := NewWithT(t)
+	ca := newTestCA(g)
+	serverDir, clientDir, intruderDir := t.TempDir(), t.TempDir(), t.TempDir()
+
+	_, err := ServerCredentials()
+	g.Expect(err).To(HaveOccurred())
+
+	ca.issue(g, serverDir, common.NodeServiceServerName, x509.ExtKeyUsageServerAuth)
+	useCertificates(t, serverDir)
+	serverCreds, err := ServerCredentials()
+	g.Expect(err).NotTo(HaveOccurred())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	s := grpc.NewServer(grpc.Creds(serverCreds))
+	pb.RegisterNodeServiceServer(s, &server{})
+	go s.Serve(lis)
+	defer s.Stop()
+	address := lis.Addr().String()
+
+	ca.issue(g, clientDir, common.NodeServiceClientName, x509.ExtKeyUsageClientAuth)
+	useCertificates(t, clientDir)
+	clientCreds, err := ClientCredentials()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(notifyUnmap(address, clientCreds)).To(Succeed())
+
+	// a certificate of the same CA which was not issued to the controller is rejected
+	ca.issue(g, intruderDir, "intruder", x509.ExtKeyUsageClientAuth)
+	useCertificates(t, intruderDir)
+	intruderCreds, err := ClientCredentials()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(notifyUnmap(address, intruderCreds)).NotTo(Succeed())
+	g.Expect(notifyUnmap(address, insecure.NewCredentials())).NotTo(Succeed())
+
+	// certificates rotated to a new CA are picked up by the running server and client
+	rotated := newTestCA(g)
+	rotated.issue(g, serverDir, common.NodeServiceServerName, x509.ExtKeyUsageServerAuth)
+	g.Expect(notifyUnmap(address, clientCreds)).NotTo(Succeed())
+	rotated.issue(g, clientDir, common.NodeServiceClientName, x509.ExtKeyUsageClientAuth)
+	g.Expect(notifyUnmap(address, clientCreds)).To(Succeed())
+}
+
+func TestPlaintextPeer(t *testing.T) {
+	g := NewWithT(t)
+	ca := newTestCA(g)
+	clientDir := t.TempDir()
+
+	// a node service released before TLS was introduced
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	s := grpc.NewServer()
+	pb.RegisterNodeServiceServer(s, &server{})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	ca.issue(g, clientDir, common.NodeServiceClientName, x509.ExtKeyUsageClientAuth)
+	useCertificates(t, clientDir)
+	clientCreds, err := ClientCredentials()
+	g.Expect(err).NotTo(HaveOccurred())
+	err = notifyUnmap(lis.Addr().String(), clientCreds)
+	g.Expect(IsPlaintextPeer(err)).To(BeTrue())
+
+	// nothing listening is not mistaken for a plaintext node service
+	s.Stop()
+	err = notifyUnmap(lis.Addr().String(), clientCreds)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(IsPlaintextPeer(err)).To(BeFalse())
+}
@@ -0,0 +1,697 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package simulator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/client"
+	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
+)
+
+// sizeUnits: size suffixes accepted by the array, longest first so that GiB is not parsed as B
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	{"TB", 1000 * 1000 * 1000 * 1000}, {"GB", 1000 * 1000 * 1000}, {"MB", 1000 * 1000}, {"KB", 1000},
+	{"B", 1},
+}
+
+// parseSize: convert an array size string (10GiB, 4096B, ...) to a number of 512 byte blocks, rounding up
+func parseSize(size string) (int64, error) {
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(size, unit.suffix) {
+			value, err := strconv.ParseInt(strings.TrimSuffix(size, unit.suffix), 10, 64)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid size %q", size)
+			}
+			bytes := value * unit.multiplier
+			return (bytes + blockSize - 1) / blockSize, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid size %q", size)
+}
+
+// formatSize: format a number of blocks the way the array displays sizes
+func formatSize(blocks int64) string {
+	return fmt.Sprintf("%.1fGB", float64(blocks*blockSize)/1e9)
+}
+
+// poolUsage: number of blocks allocated in a pool
+func (s *Simulator) poolUsage(name string) int64 {
+	var used int64
+	for _, v := range s.volumes {
+		if v.pool == name {
+			used += v.blocks
+		}
+	}
+	return used
+}
+
+// sortedVolumes: volumes and snapshots ordered by name, so responses are stable
+func (s *Simulator) sortedVolumes() []*volume {
+	volumes := make([]*volume, 0, len(s.volumes))
+	for _, v := range s.volumes {
+		volumes = append(volumes, v)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].name < volumes[j].name })
+	return volumes
+}
+
+// baseVolume: the volume at the root of a snapshot tree
+func (s *Simulator) baseVolume(v *volume) *volume {
+	for v.snapshot {
+		parent, ok := s.volumes[v.parent]
+		if !ok {
+			break
+		}
+		v = parent
+	}
+	return v
+}
+
+// hasChildren: true if snapshots were taken from the volume
+func (s *Simulator) hasChildren(name string) bool {
+	for _, v := range s.volumes {
+		if v.snapshot && v.parent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addVolume: allocate a new volume or snapshot, the caller checked name and space
+func (s *Simulator) addVolume(name, poolName string, blocks int64, snapshot bool, parent string) *volume {
+	serial := s.nextSerial()
+	v := &volume{
+		name:     name,
+		serial:   serial,
+		wwn:      strings.ToUpper("600c0ff0" + serial[8:]),
+		pool:     poolName,
+		blocks:   blocks,
+		snapshot: snapshot,
+		parent:   parent,
+		created:  time.Now().UTC(),
+		access:   map[string]string{},
+		luns:     map[string]int{},
+	}
+	s.volumes[name] = v
+	return v
+}
+
+// volumeResource: the representation of a volume in show volumes
+func volumeResource(v *volume) client.VolumesResourceInner {
+	volumeType := "base"
+	if v.snapshot {
+		volumeType = "snapshot"
+	}
+	return client.VolumesResourceInner{
+		ObjectName:       client.PtrString("volume"),
+		Meta:             client.PtrString("/meta/volumes"),
+		Blocks:           client.PtrInt64(v.blocks),
+		Blocksize:        client.PtrInt64(blockSize),
+		CreationDateTime: client.PtrString(v.created.Format(time.DateTime)),
+		Health:           client.PtrString("OK"),
+		HealthNumeric:    client.PtrInt64(0),
+		SerialNumber:     client.PtrString(v.serial),
+		Size:             client.PtrString(formatSize(v.blocks)),
+		SizeNumeric:      client.PtrInt64(v.blocks),
+		StoragePoolName:  client.PtrString(v.pool),
+		StorageType:      client.PtrString("Virtual"),
+		TierAffinity:     client.PtrString("No Affinity"),
+		TotalSize:        client.PtrString(formatSize(v.blocks)),
+		TotalSizeNumeric: client.PtrInt64(v.blocks),
+		VolumeName:       client.PtrString(v.name),
+		VolumeParent:     client.PtrString(v.parent),
+		VolumeType:       client.PtrString(volumeType),
+		Wwn:              client.PtrString(v.wwn),
+	}
+}
+
+// snapshotResource: the representation of a snapshot in show snapshots
+func (s *Simulator) snapshotResource(v *volume) client.SnapshotsResourceInner {
+	return client.SnapshotsResourceInner{
+		ObjectName:              client.PtrString("snapshot"),
+		Meta:                    client.PtrString("/meta/snapshots"),
+		BaseVolume:              client.PtrString(s.baseVolume(v).name),
+		CreationDateTime:        client.PtrString(v.created.Format(time.DateTime)),
+		CreationDateTimeNumeric: client.PtrInt64(v.created.Unix()),
+		MasterVolumeName:        client.PtrString(s.baseVolume(v).name),
+		Name:                    client.PtrString(v.name),
+		SerialNumber:            client.PtrString(v.serial),
+		StoragePoolName:         client.PtrString(v.pool),
+		StorageType:             client.PtrString("Virtual"),
+		TotalSize:               client.PtrString(formatSize(v.blocks)),
+		TotalSizeNumeric:        client.PtrInt64(v.blocks),
+		VolumeParent:            client.PtrString(v.parent),
+	}
+}
+
+// showControllers: both controllers with their host ports, controller A answers on the simulator address
+func (s *Simulator) showControllers(args []string) interface{} {
+	address := strings.TrimPrefix(s.server.URL, "http://")
+	controllers := []client.ControllersResourceInner{}
+	for _, id := range []string{"A", "B"} {
+		ipAddress := address
+		if id == "B" {
+			ipAddress = "0.0.0.0"
+		}
+		controller := client.ControllersResourceInner{
+			ObjectName:   client.PtrString("controllers"),
+			ControllerId: client.PtrString(id),
+			DurableId:    client.PtrString("controller_" + strings.ToLower(id)),
+			IpAddress:    client.PtrString(ipAddress),
+			PlatformType: client.PtrString("Simulator"),
+			SerialNumber: client.PtrString("SIM0000000" + id),
+			Status:       client.PtrString("Operational"),
+			Health:       client.PtrString("OK"),
+		}
+		for _, p := range s.ports {
+			if p.controller != id {
+				continue
+			}
+			controller.Port = append(controller.Port, client.PortResourceInner{
+				ObjectName: client.PtrString("port"),
+				Controller: client.PtrString(p.controller),
+				Port:       client.PtrString(p.label),
+				PortType:   client.PtrString(p.portType),
+				TargetId:   client.PtrString(p.targetId),
+				Status:     client.PtrString("Up"),
+				Health:     client.PtrString("OK"),
+				IscsiPort: []client.IscsiPortResourceInner{{
+					ObjectName:            client.PtrString("port-details"),
+					IpAddress:             client.PtrString(p.ipAddress),
+					SfpPresent:            client.PtrString("Present"),
+					SfpEthernetCompliance: client.PtrString("10GBase-SR"),
+				}},
+			})
+		}
+		controllers = append(controllers, controller)
+	}
+	return &client.ControllersObject{Status: success(), Controllers: controllers}
+}
+
+// showVersions: firmware versions of both controllers
+func (s *Simulator) showVersions(args []string) interface{} {
+	versions := []client.VersionsResourceInner{}
+	for _, id := range []string{"a", "b"} {
+		versions = append(versions, client.VersionsResourceInner{
+			ObjectName: client.PtrString("controller-" + id + "-versions"),
+			McFw:       client.PtrString("IN100R001-01"),
+			McBaseFw:   client.PtrString("IN100R001"),
+		})
+	}
+	return &client.VersionsObject{Status: success(), Versions: versions}
+}
+
+// sortedPools: pools ordered by name
+func (s *Simulator) sortedPools() []*pool {
+	pools := make([]*pool, 0, len(s.pools))
+	for _, p := range s.pools {
+		pools = append(pools, p)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].name < pools[j].name })
+	return pools
+}
+
+// showDiskGroups: one disk group backing each pool
+func (s *Simulator) showDiskGroups(args []string) interface{} {
+	diskGroups := []client.DiskGroupsResourceInner{}
+	for _, p := range s.sortedPools() {
+		diskGroups = append(diskGroups, client.DiskGroupsResourceInner{
+			ObjectName:       client.PtrString("disk-group"),
+			Name:             client.PtrString("dg" + p.name + "01"),
+			Pool:             client.PtrString(p.name),
+			PoolSerialNumber: client.PtrString(p.serial),
+			StorageType:      client.PtrString("Virtual"),
+			SizeNumeric:      client.PtrInt64(p.size / blockSize),
+			Status:           client.PtrString("FTOL"),
+			Health:           client.PtrString("OK"),
+		})
+	}
+	return &client.DiskGroupsObject{Status: success(), DiskGroups: diskGroups}
+}
+
+// showPools: pools with their capacity, in blocks
+func (s *Simulator) showPools(args []string) interface{} {
+	pools := []client.PoolsResourceInner{}
+	for _, p := range s.sortedPools() {
+		total := p.size / blockSize
+		avail := total - s.poolUsage(p.name)
+		pools = append(pools, client.PoolsResourceInner{
+			ObjectName:        client.PtrString("pools"),
+			Name:              client.PtrString(p.name),
+			SerialNumber:      client.PtrString(p.serial),
+			StorageType:       client.PtrString("Virtual"),
+			Blocksize:         client.PtrInt64(blockSize),
+			Health:            client.PtrString("OK"),
+			TotalSize:         client.PtrString(formatSize(total)),
+			TotalSizeNumeric:  client.PtrInt64(total),
+			TotalAvail:        client.PtrString(formatSize(avail)),
+			TotalAvailNumeric: client.PtrInt64(avail),
+			Overcommit:        client.PtrString("Enabled"),
+			OverCommitted:     client.PtrString("False"),
+		})
+	}
+	return &client.PoolsObject{Status: success(), Pools: pools}
+}
+
+// showHostGroups: the host groups with their hosts, ungrouped hosts and initiators without a host being reported in
+// the ungrouped host of the ungrouped host group
+func (s *Simulator) showHostGroups(args []string) interface{} {
+	ids := make([]string, 0, len(s.initiators))
+	for id := range s.initiators {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	hostNames := make([]string, 0, len(s.hosts))
+	for name := range s.hosts {
+		hostNames = append(hostNames, name)
+	}
+	sort.Strings(hostNames)
+
+	initiatorResource := func(id string, hostId string) client.InitiatorResourceInner {
+		return client.InitiatorResourceInner{
+			ObjectName: client.PtrString("initiator"),
+			DurableId:  client.PtrString("I" + id),
+			Id:         client.PtrString(id),
+			Nickname:   client.PtrString(s.initiators[id]),
+			HostId:     client.PtrString(hostId),
+		}
+	}
+
+	groups := map[string]*client.HostGroupResourceInner{}
+	groupNames := []string{}
+	ungrouped := &client.HostGroupResourceInner{
+		ObjectName: client.PtrString("host-group"),
+		DurableId:  client.PtrString("HGU"),
+		Name:       client.PtrString("-ungrouped-"),
+	}
+	inHost := map[string]bool{}
+	for i, name := range hostNames {
+		h := s.hosts[name]
+		initiators := []client.InitiatorResourceInner{}
+		for _, id := range h.initiators {
+			initiators = append(initiators, initiatorResource(id, fmt.Sprintf("H%d", i)))
+			inHost[id] = true
+		}
+		hostResource := client.HostResourceInner{
+			ObjectName:  client.PtrString("host"),
+			DurableId:   client.PtrString(fmt.Sprintf("H%d", i)),
+			Name:        client.PtrString(h.name),
+			MemberCount: client.PtrInt64(int64(len(initiators))),
+			Initiator:   initiators,
+		}
+		group := ungrouped
+		if h.group != "" {
+			if groups[h.group] == nil {
+				groups[h.group] = &client.HostGroupResourceInner{
+					ObjectName: client.PtrString("host-group"),
+					DurableId:  client.PtrString(fmt.Sprintf("HG%d", len(groupNames))),
+					Name:       client.PtrString(h.group),
+				}
+				groupNames = append(groupNames, h.group)
+			}
+			group = groups[h.group]
+		}
+		group.Host = append(group.Host, hostResource)
+	}
+
+	initiators := []client.InitiatorResourceInner{}
+	for _, id := range ids {
+		if !inHost[id] {
+			initiators = append(initiators, initiatorResource(id, "NOHOST"))
+		}
+	}
+	ungrouped.Host = append(ungrouped.Host, client.HostResourceInner{
+		ObjectName:  client.PtrString("host"),
+		DurableId:   client.PtrString("HU"),
+		Name:        client.PtrString("-nohost-"),
+		MemberCount: client.PtrInt64(int64(len(initiators))),
+		Initiator:   initiators,
+	})
+
+	hostGroups := []client.HostGroupResourceInner{}
+	for _, name := range groupNames {
+		groups[name].MemberCount = client.PtrInt64(int64(len(groups[name].Host)))
+		hostGroups = append(hostGroups, *groups[name])
+	}
+	ungrouped.MemberCount = client.PtrInt64(int64(len(ungrouped.Host)))
+	hostGroups = append(hostGroups, *ungrouped)
+	return &client.HostGroupObject{Status: success(), HostGroup: hostGroups}
+}
+
+// showVolumes: volumes and snapshots matching a comma separated list of names
+func (s *Simulator) showVolumes(args []string) interface{} {
+	volumes := []client.VolumesResourceInner{}
+	for _, name := range strings.Split(args[0], ",") {
+		v, ok := s.volumes[name]
+		if !ok {
+			return &client.VolumesObject{Status: failure(common.BadInputParam, "The volume %s was not found on this system.", name)}
+		}
+		volumes = append(volumes, volumeResource(v))
+	}
+	return &client.VolumesObject{Status: success(), Volumes: volumes}
+}
+
+// showSnapshots: every snapshot on the system
+func (s *Simulator) showSnapshots(args []string) interface{} {
+	snapshots := []client.SnapshotsResourceInner{}
+	for _, v := range s.sortedVolumes() {
+		if v.snapshot {
+			snapshots = append(snapshots, s.snapshotResource(v))
+		}
+	}
+	return &client.SnapshotsObject{Status: success(), Snapshots: snapshots}
+}
+
+// showSnapshotsPattern: snapshots whose name matches a pattern
+func (s *Simulator) showSnapshotsPattern(args []string) interface{} {
+	snapshots := []client.SnapshotsResourceInner{}
+	for _, v := range s.sortedVolumes() {
+		if matched, _ := path.Match(args[0], v.name); v.snapshot && matched {
+			snapshots = append(snapshots, s.snapshotResource(v))
+		}
+	}
+	return &client.SnapshotsObject{Status: success(), Snapshots: snapshots}
+}
+
+// showSnapshotsVolume: snapshots taken from a volume
+func (s *Simulator) showSnapshotsVolume(args []string) interface{} {
+	source, ok := s.volumes[args[0]]
+	if !ok {
+		return &client.SnapshotsObject{Status: failure(common.BadInputParam, "The volume %s was not found on this system.", args[0])}
+	}
+	snapshots := []client.SnapshotsResourceInner{}
+	for _, v := range s.sortedVolumes() {
+		if v.snapshot && s.baseVolume(v) == source {
+			snapshots = append(snapshots, s.snapshotResource(v))
+		}
+	}
+	return &client.SnapshotsObject{Status: success(), Snapshots: snapshots}
+}
+
+// showMapsInitiator: volumes mapped to an initiator given by id or nickname, or to the initiators of a "host.*" or
+// "host-group.*.*" target
+func (s *Simulator) showMapsInitiator(args []string) interface{} {
+	ids := s.targetInitiators(args[0])
+	if len(ids) == 0 {
+		return statusObject(failure(common.InitiatorNicknameOrIdentifierNotFound, "The initiator %s was not found on this system.", args[0]))
+	}
+
+	mappings := []client.HostViewMappingsResourceInner{}
+	for _, v := range s.sortedVolumes() {
+		for _, id := range ids {
+			if lun, ok := v.luns[id]; ok {
+				mappings = append(mappings, client.HostViewMappingsResourceInner{
+					ObjectName:   client.PtrString("volume-view"),
+					Access:       client.PtrString(v.access[id]),
+					Lun:          client.PtrString(strconv.Itoa(lun)),
+					Ports:        client.PtrString("A0,B0"),
+					Volume:       client.PtrString(v.name),
+					VolumeName:   client.PtrString(v.name),
+					VolumeSerial: client.PtrString(v.serial),
+				})
+				break
+			}
+		}
+	}
+
+	return &client.InitiatorViewObject{
+		Status: success(),
+		InitiatorView: []client.InitiatorViewResourceInner{{
+			ObjectName:       client.PtrString("initiator-view"),
+			Id:               client.PtrString(ids[0]),
+			HbaNickname:      client.PtrString(s.initiators[ids[0]]),
+			HostViewMappings: mappings,
+		}},
+	}
+}
+
+// targetInitiators: resolve a mapping target to initiator ids. Targets are an initiator id or nickname, "host.*" for
+// the initiators of a host, or "host-group.*.*" for the initiators of every host of a host group.
+func (s *Simulator) targetInitiators(target string) []string {
+	ids := []string{}
+	switch {
+	case strings.HasSuffix(target, ".*.*"):
+		group := strings.TrimSuffix(target, ".*.*")
+		for _, h := range s.hosts {
+			if h.group == group {
+				ids = append(ids, h.initiators...)
+			}
+		}
+		sort.Strings(ids)
+	case strings.HasSuffix(target, ".*"):
+		if h, ok := s.hosts[strings.TrimSuffix(target, ".*")]; ok {
+			ids = append(ids, h.initiators...)
+		}
+	default:
+		if id := s.initiatorId(target); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// initiatorId: resolve an initiator id or nickname to a known initiator id
+func (s *Simulator) initiatorId(initiator string) string {
+	if _, ok := s.initiators[initiator]; ok {
+		return initiator
+	}
+	for id, nickname := range s.initiators {
+		if nickname != "" && nickname == initiator {
+			return id
+		}
+	}
+	return ""
+}
+
+// createVolume: allocate a volume in a pool
+func (s *Simulator) createVolume(args []string) interface{} {
+	poolName, size, name := args[0], args[1], args[2]
+	p, ok := s.pools[poolName]
+	if !ok {
+		return &client.VolumesObject{Status: failure(poolNotFoundErrorCode, "The pool %s was not found on this system.", poolName)}
+	}
+	if _, exists := s.volumes[name]; exists {
+		return &client.VolumesObject{Status: failure(nameInUseErrorCode, "The name %s is already in use.", name)}
+	}
+	blocks, err := parseSize(size)
+	if err != nil {
+		return &client.VolumesObject{Status: failure(common.InvalidArgumentErrorCode, "%v", err)}
+	}
+	if s.poolUsage(p.name)+blocks > p.size/blockSize {
+		return &client.VolumesObject{Status: failure(insufficientSpaceErrorCode, "There is not enough available space in pool %s.", poolName)}
+	}
+	v := s.addVolume(name, p.name, blocks, false, "")
+	return &client.VolumesObject{Status: success(), Volumes: []client.VolumesResourceInner{volumeResource(v)}}
+}
+
+// createVolumeTierAffinity: tier affinity has no effect on a simulated pool
+func (s *Simulator) createVolumeTierAffinity(args []string) interface{} {
+	return s.createVolume([]string{args[0], args[1], args[3]})
+}
+
+// expandVolume: grow a volume by the given size
+func (s *Simulator) expandVolume(args []string) interface{} {
+	size, name := args[0], args[1]
+	v, ok := s.volumes[name]
+	if !ok || v.snapshot {
+		return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", name))
+	}
+	blocks, err := parseSize(size)
+	if err != nil {
+		return statusObject(failure(common.InvalidArgumentErrorCode, "%v", err))
+	}
+	if s.poolUsage(v.pool)+blocks > s.pools[v.pool].size/blockSize {
+		return statusObject(failure(insufficientSpaceErrorCode, "There is not enough available space in pool %s.", v.pool))
+	}
+	v.blocks += blocks
+	return statusObject(success())
+}
+
+// copyVolume: create a new base volume from a volume or a snapshot
+func (s *Simulator) copyVolume(args []string) interface{} {
+	poolName, name, sourceName := args[0], args[1], args[2]
+	source, ok := s.volumes[sourceName]
+	if !ok {
+		return statusObject(failure(common.SnapshotNotFoundErrorCode, "The source %s was not found on this system.", sourceName))
+	}
+	p, ok := s.pools[poolName]
+	if !ok {
+		return statusObject(failure(poolNotFoundErrorCode, "The pool %s was not found on this system.", poolName))
+	}
+	if _, exists := s.volumes[name]; exists {
+		return statusObject(failure(nameInUseErrorCode, "The name %s is already in use.", name))
+	}
+	if s.poolUsage(p.name)+source.blocks > p.size/blockSize {
+		return statusObject(failure(insufficientSpaceErrorCode, "There is not enough available space in pool %s.", poolName))
+	}
+	s.addVolume(name, p.name, source.blocks, false, "")
+	return statusObject(success())
+}
+
+// deleteVolumes: delete a comma separated list of volumes, refusing volumes which have snapshots
+func (s *Simulator) deleteVolumes(args []string) interface{} {
+	names := strings.Split(args[0], ",")
+	for _, name := range names {
+		if _, ok := s.volumes[name]; !ok {
+			return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", name))
+		}
+		if s.hasChildren(name) {
+			return statusObject(failure(common.VolumeHasSnapshot, "The volume %s has snapshots and cannot be deleted.", name))
+		}
+	}
+	for _, name := range names {
+		delete(s.volumes, name)
+	}
+	return statusObject(success())
+}
+
+// mapVolume: present a volume to an initiator with the given LUN and access
+func (s *Simulator) mapVolume(args []string) interface{} {
+	access, lunStr, initiator, name := args[0], args[1], args[2], args[3]
+	v, ok := s.volumes[name]
+	if !ok {
+		return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", name))
+	}
+	lun, err := strconv.Atoi(lunStr)
+	if err != nil || lun < 0 || lun > maximumLUN {
+		return statusObject(failure(common.InvalidArgumentErrorCode, "The LUN %s is not valid.", lunStr))
+	}
+
+	// Initiators are considered connected to the array, so unknown ids are added to the initiator table
+	ids := s.targetInitiators(initiator)
+	if len(ids) == 0 {
+		if strings.HasSuffix(initiator, ".*") {
+			return statusObject(failure(common.InitiatorNicknameOrIdentifierNotFound, "The host %s was not found on this system.", initiator))
+		}
+		ids = []string{initiator}
+		s.initiators[initiator] = ""
+	}
+
+	for _, id := range ids {
+		for _, other := range s.volumes {
+			if otherLun, mapped := other.luns[id]; mapped && other != v && otherLun == lun {
+				return statusObject(failure(common.LUNOverlapErrorCode, "The LUN %d is already in use by volume %s.", lun, other.name))
+			}
+		}
+	}
+
+	for _, id := range ids {
+		v.luns[id] = lun
+		v.access[id] = "read-write"
+		if access == "ro" || access == "read-only" {
+			v.access[id] = "read-only"
+		}
+	}
+	return statusObject(success())
+}
+
+// unmapVolumeInitiator: remove the mapping of a volume for one initiator
+func (s *Simulator) unmapVolumeInitiator(args []string) interface{} {
+	initiator, name := args[0], args[1]
+	v, ok := s.volumes[name]
+	if !ok {
+		return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", name))
+	}
+	unmapped := false
+	for _, id := range s.targetInitiators(initiator) {
+		if _, mapped := v.luns[id]; mapped {
+			delete(v.luns, id)
+			delete(v.access, id)
+			unmapped = true
+		}
+	}
+	if !unmapped {
+		return statusObject(failure(common.UnmapFailedErrorCode, "The volume %s is not mapped to %s.", name, initiator))
+	}
+	return statusObject(success())
+}
+
+// unmapVolume: remove all the mappings of a volume
+func (s *Simulator) unmapVolume(args []string) interface{} {
+	v, ok := s.volumes[args[0]]
+	if !ok {
+		return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", args[0]))
+	}
+	if len(v.luns) == 0 {
+		return statusObject(failure(common.UnmapFailedErrorCode, "The volume %s is not mapped.", args[0]))
+	}
+	v.luns = map[string]int{}
+	v.access = map[string]string{}
+	return statusObject(success())
+}
+
+// deleteHosts: delete a comma separated list of hosts, their initiators become ungrouped initiators
+func (s *Simulator) deleteHosts(args []string) interface{} {
+	names := strings.Split(args[0], ",")
+	for _, name := range names {
+		if _, ok := s.hosts[name]; !ok {
+			return statusObject(failure(common.BadInputParam, "The host %s was not found on this system.", name))
+		}
+	}
+	for _, name := range names {
+		delete(s.hosts, name)
+	}
+	return statusObject(success())
+}
+
+// setInitiatorNickname: add an initiator to the initiator table or rename it
+func (s *Simulator) setInitiatorNickname(args []string) interface{} {
+	s.initiators[args[0]] = args[1]
+	return statusObject(success())
+}
+
+// createSnapshots: take a snapshot of a volume, or of another snapshot
+func (s *Simulator) createSnapshots(args []string) interface{} {
+	sourceName, name := args[0], args[1]
+	source, ok := s.volumes[sourceName]
+	if !ok {
+		return statusObject(failure(common.VolumeNotFoundErrorCode, "The volume %s was not found on this system.", sourceName))
+	}
+	if existing, exists := s.volumes[name]; exists {
+		if existing.snapshot {
+			return statusObject(failure(common.SnapshotAlreadyExists, "The snapshot %s already exists.", name))
+		}
+		return statusObject(failure(nameInUseErrorCode, "The name %s is already in use.", name))
+	}
+	s.addVolume(name, source.pool, source.blocks, true, source.name)
+	return statusObject(success())
+}
+
+// deleteSnapshot: delete a snapshot which has no snapshots of its own
+func (s *Simulator) deleteSnapshot(args []string) interface{} {
+	v, ok := s.volumes[args[0]]
+	if !ok || !v.snapshot {
+		return statusObject(failure(common.SnapshotNotFoundErrorCode, "The snapshot %s was not found on this system.", args[0]))
+	}
+	if s.hasChildren(v.name) {
+		return statusObject(failure(common.VolumeHasSnapshot, "The snapshot %s has snapshots and cannot be deleted.", v.name))
+	}
+	delete(s.volumes, v.name)
+	return statusObject(success())
+}
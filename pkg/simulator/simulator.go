@@ -0,0 +1,344 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+// Package simulator serves the subset of the Exos X management API used by the driver, backed
+// by in-memory state, so that the controller can be exercised without a storage array. Responses are JSON when the
+// request has the "datatype: json" header the API library sends, and XML otherwise, as on the array.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-api-go/v2/pkg/client"
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultUsername  = "manage"
+	DefaultPassword  = "!manage"
+	DefaultPool      = "A"
+	DefaultPoolSize  = int64(64) << 40
+	DefaultTargetIQN = "iqn.1988-11.com.dothill:01.array.00c0ff5a0000"
+
+	blockSize  = int64(512)
+	maximumLUN = 1023
+)
+
+// Return codes used by the simulator which are not exported by the API library
+const (
+	loginFailedReturnCode      = 2
+	nameInUseErrorCode         = -10029
+	insufficientSpaceErrorCode = -10068
+	poolNotFoundErrorCode      = -10111
+	unknownCommandErrorCode    = -10001
+)
+
+// pool: a virtual storage pool
+type pool struct {
+	name   string
+	serial string
+	size   int64
+}
+
+// volume: a base volume or a snapshot, both share the same namespace on the array
+type volume struct {
+	name     string
+	serial   string
+	wwn      string
+	pool     string
+	blocks   int64
+	snapshot bool
+	parent   string
+	created  time.Time
+	access   map[string]string
+	luns     map[string]int
+}
+
+// host: a named group of initiators, optionally part of a host group
+type host struct {
+	name       string
+	group      string
+	initiators []string
+}
+
+// port: a host port of one of the two controllers
+type port struct {
+	label      string
+	controller string
+	portType   string
+	targetId   string
+	ipAddress  string
+}
+
+// Simulator: an in-memory Exos X array served over httptest
+type Simulator struct {
+	Username string
+	Password string
+
+	server     *httptest.Server
+	mu         sync.Mutex
+	sessions   map[string]bool
+	pools      map[string]*pool
+	volumes    map[string]*volume
+	initiators map[string]string
+	hosts      map[string]*host
+	ports      []port
+	serial     int
+}
+
+// New: start a simulated array with a single pool and an iSCSI port on each controller
+func New() *Simulator {
+	s := &Simulator{
+		Username:   DefaultUsername,
+		Password:   DefaultPassword,
+		sessions:   map[string]bool{},
+		pools:      map[string]*pool{},
+		volumes:    map[string]*volume{},
+		initiators: map[string]string{},
+		hosts:      map[string]*host{},
+		ports: []port{
+			{label: "A0", controller: "A", portType: "iSCSI", targetId: DefaultTargetIQN, ipAddress: "192.0.2.10"},
+			{label: "B0", controller: "B", portType: "iSCSI", targetId: DefaultTargetIQN, ipAddress: "192.0.2.11"},
+		},
+	}
+	s.AddPool(DefaultPool, DefaultPoolSize)
+	s.server = httptest.NewServer(s)
+	klog.V(2).InfoS("started array simulator", "url", s.server.URL)
+	return s
+}
+
+// URL: the address of the simulated management controller, to be used as apiAddress
+func (s *Simulator) URL() string {
+	return s.server.URL
+}
+
+// Close: stop serving the API
+func (s *Simulator) Close() {
+	s.server.Close()
+}
+
+// AddPool: create a virtual pool of the given size in bytes
+func (s *Simulator) AddPool(name string, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pools[name] = &pool{name: name, serial: s.nextSerial(), size: size}
+}
+
+// AddHost: create a host holding the initiators, in a host group unless group is empty
+func (s *Simulator) AddHost(name string, group string, initiators ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, initiator := range initiators {
+		if _, ok := s.initiators[initiator]; !ok {
+			s.initiators[initiator] = ""
+		}
+	}
+	s.hosts[name] = &host{name: name, group: group, initiators: initiators}
+}
+
+// HasVolume: return true if a volume or snapshot exists on the array
+func (s *Simulator) HasVolume(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.volumes[name]
+	return ok
+}
+
+// Mappings: return the LUN of a volume for each initiator it is mapped to
+func (s *Simulator) Mappings(name string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mappings := map[string]int{}
+	if v, ok := s.volumes[name]; ok {
+		for initiator, lun := range v.luns {
+			mappings[initiator] = lun
+		}
+	}
+	return mappings
+}
+
+// nextSerial: generate a unique serial number, the caller must hold the lock
+func (s *Simulator) nextSerial() string {
+	s.serial++
+	return fmt.Sprintf("00c0ff5a%024x", s.serial)
+}
+
+// ServeHTTP: authenticate and dispatch an API command
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	args, err := splitCommand(r.URL.EscapedPath())
+	if err != nil || len(args) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	klog.V(4).InfoS("simulator command", "command", strings.Join(args, " "))
+
+	var response interface{}
+	switch {
+	case len(args) == 1 && args[0] == "login":
+		response = s.login(r)
+	case !s.sessions[r.Header.Get("sessionKey")]:
+		response = statusObject(failure(loginFailedReturnCode, "Invalid sessionkey"))
+	case len(args) == 1 && args[0] == "logout":
+		delete(s.sessions, r.Header.Get("sessionKey"))
+		response = statusObject(success())
+	default:
+		response = s.dispatch(args)
+	}
+
+	// The API library asks for JSON, other clients get the XML the array returns by default
+	if r.Header.Get("datatype") != "json" {
+		output, err := encodeXML(strings.Join(args, " "), response)
+		if err != nil {
+			klog.ErrorS(err, "unable to encode simulator response")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write(output)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		klog.ErrorS(err, "unable to encode simulator response")
+	}
+}
+
+// splitCommand: turn /api/show/volumes/name into its unescaped path segments
+func splitCommand(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.Trim(path, "/"), "api")
+	args := []string{}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		arg, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// login: open a session when the basic auth credentials match
+func (s *Simulator) login(r *http.Request) interface{} {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != s.Username || password != s.Password {
+		return statusObject(failure(loginFailedReturnCode, "Authentication Unsuccessful"))
+	}
+	sessionKey := uuid.NewString()
+	s.sessions[sessionKey] = true
+	return statusObject([]client.StatusResourceInner{statusResource("Success", 0, sessionKey, 1)})
+}
+
+// route: an API command, "*" segments are passed to the handler as arguments
+type route struct {
+	pattern []string
+	handler func(s *Simulator, args []string) interface{}
+}
+
+var routes = []route{
+	{[]string{"show", "controllers"}, (*Simulator).showControllers},
+	{[]string{"show", "versions", "detail"}, (*Simulator).showVersions},
+	{[]string{"show", "disk-groups"}, (*Simulator).showDiskGroups},
+	{[]string{"show", "pools"}, (*Simulator).showPools},
+	{[]string{"show", "host-groups"}, (*Simulator).showHostGroups},
+	{[]string{"show", "volumes", "*"}, (*Simulator).showVolumes},
+	{[]string{"show", "snapshots"}, (*Simulator).showSnapshots},
+	{[]string{"show", "snapshots", "pattern", "*"}, (*Simulator).showSnapshotsPattern},
+	{[]string{"show", "snapshots", "volume", "*"}, (*Simulator).showSnapshotsVolume},
+	{[]string{"show", "maps", "initiator", "*"}, (*Simulator).showMapsInitiator},
+	{[]string{"create", "volume", "pool", "*", "size", "*", "*"}, (*Simulator).createVolume},
+	{[]string{"create", "volume", "pool", "*", "size", "*", "tier-affinity", "*", "*"}, (*Simulator).createVolumeTierAffinity},
+	{[]string{"expand", "volume", "size", "*", "*"}, (*Simulator).expandVolume},
+	{[]string{"copy", "volume", "destination-pool", "*", "name", "*", "*"}, (*Simulator).copyVolume},
+	{[]string{"delete", "volumes", "*"}, (*Simulator).deleteVolumes},
+	{[]string{"map", "volume", "access", "*", "lun", "*", "initiator", "*", "*"}, (*Simulator).mapVolume},
+	{[]string{"unmap", "volume", "initiator", "*", "*"}, (*Simulator).unmapVolumeInitiator},
+	{[]string{"unmap", "volume", "*"}, (*Simulator).unmapVolume},
+	{[]string{"set", "initiator", "id", "*", "nickname", "*"}, (*Simulator).setInitiatorNickname},
+	{[]string{"delete", "hosts", "*"}, (*Simulator).deleteHosts},
+	{[]string{"create", "snapshots", "volumes", "*", "*"}, (*Simulator).createSnapshots},
+	{[]string{"delete", "snapshot", "*"}, (*Simulator).deleteSnapshot},
+}
+
+// dispatch: run the handler of the first route matching the command
+func (s *Simulator) dispatch(args []string) interface{} {
+	for _, r := range routes {
+		if len(r.pattern) != len(args) {
+			continue
+		}
+		params := []string{}
+		matched := true
+		for i, segment := range r.pattern {
+			if segment == "*" {
+				params = append(params, args[i])
+			} else if segment != args[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r.handler(s, params)
+		}
+	}
+	return statusObject(failure(unknownCommandErrorCode, fmt.Sprintf("The command is ambiguous or not supported: %s", strings.Join(args, " "))))
+}
+
+// statusResource: build one entry of the status array returned with every response
+func statusResource(responseType string, responseTypeNumeric int32, response string, returnCode int32) client.StatusResourceInner {
+	now := time.Now()
+	return client.StatusResourceInner{
+		ObjectName:          client.PtrString("status"),
+		Meta:                client.PtrString("/meta/status"),
+		ResponseType:        client.PtrString(responseType),
+		ResponseTypeNumeric: client.PtrInt32(responseTypeNumeric),
+		Response:            client.PtrString(response),
+		ReturnCode:          client.PtrInt32(returnCode),
+		TimeStamp:           client.PtrString(now.UTC().Format(time.DateTime)),
+		TimeStampNumeric:    client.PtrInt32(int32(now.Unix())),
+	}
+}
+
+// success: the status of a command which completed
+func success() []client.StatusResourceInner {
+	return []client.StatusResourceInner{statusResource("Success", 0, "Command completed successfully.", 0)}
+}
+
+// failure: the status of a command rejected by the array with its return code
+func failure(returnCode int, format string, a ...interface{}) []client.StatusResourceInner {
+	return []client.StatusResourceInner{statusResource("Error", 1, fmt.Sprintf(format, a...), int32(returnCode))}
+}
+
+// statusObject: a response only carrying a status
+func statusObject(status []client.StatusResourceInner) *client.StatusObject {
+	return &client.StatusObject{Status: status}
+}
@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package simulator
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"testing"
+
+	storageapi "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/api"
+	storageapitypes "github.com/Seagate/seagate-exos-x-api-go/v2/pkg/common"
+	. "github.com/onsi/gomega"
+)
+
+func newClient(t *testing.T, sim *Simulator) *storageapi.Client {
+	client := storageapi.NewClient()
+	client.StoreCredentials([]string{sim.URL()}, "", sim.Username, sim.Password)
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	return client
+}
+
+func TestLogin(t *testing.T) {
+	g := NewWithT(t)
+	sim := New()
+	defer sim.Close()
+
+	client := storageapi.NewClient()
+	client.StoreCredentials([]string{sim.URL()}, "", sim.Username, "wrong")
+	g.Expect(client.Login(context.Background())).NotTo(Succeed())
+
+	client = newClient(t, sim)
+	g.Expect(client.InitSystemInfo()).To(Succeed())
+	g.Expect(client.Info.Controller).To(Equal("A"))
+	g.Expect(client.GetPortals()).To(Equal("192.0.2.10,192.0.2.11"))
+	g.Expect(storageapi.GetTargetId(client.Info, "iSCSI")).To(Equal(DefaultTargetIQN))
+	g.Expect(client.GetPoolType(DefaultPool)).To(Equal("Virtual"))
+}
+
+func TestVolumes(t *testing.T) {
+	g := NewWithT(t)
+	sim := New()
+	defer sim.Close()
+	client := newClient(t, sim)
+
+	volume, status, err := client.CreateVolume("vol1", "1GiB", DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	g.Expect(volume.Blocks * volume.BlockSize).To(Equal(int64(1 << 30)))
+	g.Expect(volume.Wwn).To(HaveLen(32))
+
+	g.Expect(client.CheckVolumeExists("vol1", 1<<30)).To(BeTrue())
+	_, err = client.CheckVolumeExists("vol1", 2<<30)
+	g.Expect(err).To(HaveOccurred())
+
+	status, err = client.ExpandVolume("vol1", "1GiB")
+	g.Expect(err).NotTo(HaveOccurred())
+	volumes, _, err := client.ShowVolumes("vol1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(volumes[0].Blocks * volumes[0].BlockSize).To(Equal(int64(2 << 30)))
+
+	status, err = client.CopyVolume("vol1", "vol2", DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	g.Expect(sim.HasVolume("vol2")).To(BeTrue())
+
+	status, _ = client.CopyVolume("missing", "vol3", DefaultPool)
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.SnapshotNotFoundErrorCode))
+
+	status, _ = client.DeleteVolume("vol2")
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	status, _ = client.DeleteVolume("vol2")
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.VolumeNotFoundErrorCode))
+}
+
+func TestMappings(t *testing.T) {
+	g := NewWithT(t)
+	sim := New()
+	defer sim.Close()
+	client := newClient(t, sim)
+
+	initiator := "iqn.1993-08.org.debian:01:node1"
+	_, _, err := client.CreateVolume("vol1", "1GiB", DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, _, err = client.CreateVolume("vol2", "1GiB", DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(client.PublishVolume("vol1", []string{initiator})).To(Equal("1"))
+	g.Expect(client.PublishVolume("vol1", []string{initiator})).To(Equal("1"))
+	g.Expect(client.PublishVolume("vol2", []string{initiator})).To(Equal("2"))
+	g.Expect(sim.Mappings("vol2")).To(Equal(map[string]int{initiator: 2}))
+
+	status, _ := client.MapVolume("vol2", initiator, "rw", 1)
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.LUNOverlapErrorCode))
+
+	status, _ = client.UnmapVolume("vol1", initiator)
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	status, _ = client.UnmapVolume("vol1", initiator)
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.UnmapFailedErrorCode))
+	g.Expect(sim.Mappings("vol1")).To(BeEmpty())
+}
+
+func TestSnapshots(t *testing.T) {
+	g := NewWithT(t)
+	sim := New()
+	defer sim.Close()
+	client := newClient(t, sim)
+
+	_, _, err := client.CreateVolume("vol1", "1GiB", DefaultPool)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	status, _ := client.CreateSnapshot("vol1", "snap1")
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	status, _ = client.CreateSnapshot("vol1", "snap1")
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.SnapshotAlreadyExists))
+
+	snapshots, _, err := client.ShowSnapshots("snap1", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshots).To(HaveLen(1))
+	g.Expect(snapshots[0].MasterVolumeName).To(Equal("vol1"))
+	g.Expect(snapshots[0].CreationTime).NotTo(BeNil())
+
+	snapshots, _, _ = client.ShowSnapshots("", "vol1")
+	g.Expect(snapshots).To(HaveLen(1))
+	_, status, _ = client.ShowSnapshots("", "missing")
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.BadInputParam))
+
+	status, _ = client.DeleteVolume("vol1")
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.VolumeHasSnapshot))
+
+	status, _ = client.DeleteSnapshot("snap1")
+	g.Expect(status.ResponseTypeNumeric).To(Equal(0))
+	status, _ = client.DeleteSnapshot("snap1")
+	g.Expect(status.ReturnCode).To(Equal(storageapitypes.SnapshotNotFoundErrorCode))
+}
+
+// xmlGet: run a command without the "datatype: json" header and decode the XML response
+func xmlGet(t *testing.T, sim *Simulator, command string, sessionKey string) xmlResponse {
+	request, _ := http.NewRequest(http.MethodGet, sim.URL()+"/api/"+command, nil)
+	if sessionKey == "" {
+		request.SetBasicAuth(sim.Username, sim.Password)
+	} else {
+		request.Header.Set("sessionKey", sessionKey)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%s failed: %v", command, err)
+	}
+	defer response.Body.Close()
+	document := xmlResponse{}
+	if err := xml.NewDecoder(response.Body).Decode(&document); err != nil {
+		t.Fatalf("%s returned invalid XML: %v", command, err)
+	}
+	return document
+}
+
+// property: the value of a property of an XML object
+func (object xmlObject) property(name string) string {
+	for _, property := range object.Properties {
+		if property.Name == name {
+			return property.Value
+		}
+	}
+	return ""
+}
+
+func TestXMLResponses(t *testing.T) {
+	g := NewWithT(t)
+	sim := New()
+	defer sim.Close()
+
+	login := xmlGet(t, sim, "login", "")
+	g.Expect(login.Objects).To(HaveLen(1))
+	g.Expect(login.Objects[0].Basetype).To(Equal("status"))
+	g.Expect(login.Objects[0].property("return-code")).To(Equal("1"))
+	sessionKey := login.Objects[0].property("response")
+
+	pools := xmlGet(t, sim, "show/pools", sessionKey)
+	g.Expect(pools.Request).To(Equal("show pools"))
+	g.Expect(pools.Objects).To(HaveLen(2))
+	g.Expect(pools.Objects[0].Basetype).To(Equal("pools"))
+	g.Expect(pools.Objects[0].Name).To(Equal("pools"))
+	g.Expect(pools.Objects[0].property("name")).To(Equal(DefaultPool))
+	g.Expect(pools.Objects[1].Basetype).To(Equal("status"))
+	g.Expect(pools.Objects[1].Oid).To(Equal(2))
+
+	missing := xmlGet(t, sim, "show/volumes/missing", sessionKey)
+	status := missing.Objects[len(missing.Objects)-1]
+	g.Expect(status.property("response-type")).To(Equal("Error"))
+	g.Expect(status.property("return-code")).To(Equal(strconv.Itoa(storageapitypes.BadInputParam)))
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+)
+
+// xmlResponse: the XML form of an API response, returned when the request has no "datatype: json" header
+type xmlResponse struct {
+	XMLName xml.Name    `xml:"RESPONSE"`
+	Version string      `xml:"VERSION,attr"`
+	Request string      `xml:"REQUEST,attr"`
+	Objects []xmlObject `xml:"OBJECT"`
+}
+
+type xmlObject struct {
+	Basetype   string        `xml:"basetype,attr"`
+	Name       string        `xml:"name,attr"`
+	Oid        int           `xml:"oid,attr"`
+	Properties []xmlProperty `xml:"PROPERTY"`
+	Objects    []xmlObject   `xml:"OBJECT"`
+}
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// encodeXML: render a response in the XML format of the array, where each JSON array becomes a list of
+// OBJECT elements and each scalar field a PROPERTY. The status object comes last, as on the array.
+func encodeXML(request string, response interface{}) ([]byte, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	fields := map[string]interface{}{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	oid := 0
+	document := xmlResponse{Version: "L100", Request: request, Objects: xmlObjects(fields, &oid)}
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+// xmlObjects: the OBJECT elements of the array fields of a JSON object, numbered from oid
+func xmlObjects(fields map[string]interface{}, oid *int) []xmlObject {
+	basetypes := []string{}
+	for key, value := range fields {
+		if _, ok := value.([]interface{}); ok && key != "status" {
+			basetypes = append(basetypes, key)
+		}
+	}
+	sort.Strings(basetypes)
+	if _, ok := fields["status"].([]interface{}); ok {
+		basetypes = append(basetypes, "status")
+	}
+
+	objects := []xmlObject{}
+	for _, basetype := range basetypes {
+		for _, item := range fields[basetype].([]interface{}) {
+			itemFields, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			*oid++
+			object := xmlObject{Basetype: basetype, Name: basetype, Oid: *oid}
+			if name, ok := itemFields["object-name"].(string); ok {
+				object.Name = name
+			}
+			object.Properties = xmlProperties(itemFields)
+			object.Objects = xmlObjects(itemFields, oid)
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// xmlProperties: the PROPERTY elements of the scalar fields of a JSON object, in name order
+func xmlProperties(fields map[string]interface{}) []xmlProperty {
+	names := []string{}
+	for name := range fields {
+		if name != "object-name" && name != "meta" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	properties := []xmlProperty{}
+	for _, name := range names {
+		switch value := fields[name].(type) {
+		case string:
+			properties = append(properties, xmlProperty{Name: name, Type: "string", Value: value})
+		case json.Number:
+			properties = append(properties, xmlProperty{Name: name, Type: "sint64", Value: value.String()})
+		case bool:
+			properties = append(properties, xmlProperty{Name: name, Type: "bool", Value: map[bool]string{true: "true", false: "false"}[value]})
+		}
+	}
+	return properties
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// dryRunStorage logs the attach, detach and expand operations of a protocol instead of running them through the
+// iSCSI, FC and SAS libraries, which run their own host commands
+type dryRunStorage struct {
+	cs                commonService
+	connectorInfoPath string
+	protocol          string
+}
+
+// dryRunHost creates dry-run storage nodes, the initiators and monitors of the host are still used since they only
+// read the host, and LUN rescans only log through the DryRunRunner
+type dryRunHost struct {
+	hostStorage
+}
+
+// UseDryRunStorage: Only log the host commands and the device operations of the node plugin, for the -dryrun flag
+func UseDryRunStorage() {
+	SetCommandRunner(&DryRunRunner{})
+	nodeStorage = &dryRunHost{}
+}
+
+func (host *dryRunHost) newStorage(storageProtocol string, cs commonService, connectorInfoPath string) StorageOperations {
+	return &dryRunStorage{cs: cs, connectorInfoPath: connectorInfoPath, protocol: storageProtocol}
+}
+
+// devicePath: Return the multipath device a volume would be attached as
+func (dryRun *dryRunStorage) devicePath(volumeId string) (string, error) {
+	wwn, err := common.VolumeIdGetWwn(volumeId)
+	if err != nil || wwn == "" {
+		return "", fmt.Errorf("no WWN found in volume id (%s)", volumeId)
+	}
+	return fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn), nil
+}
+
+// NodeStageVolume mounts the volume to a staging path on the node.
+// Will not be called as the plugin does not have the STAGE_UNSTAGE_VOLUME capability
+func (dryRun *dryRunStorage) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeStageVolume is not implemented")
+}
+
+// NodeUnstageVolume unstages the volume from the staging path
+// Will not be called as the plugin does not have the STAGE_UNSTAGE_VOLUME capability
+func (dryRun *dryRunStorage) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeUnstageVolume is not implemented")
+}
+
+func (dryRun *dryRunStorage) AttachStorage(ctx context.Context, req *csi.NodePublishVolumeRequest) (string, error) {
+	path, err := dryRun.devicePath(req.GetVolumeId())
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	klog.InfoS("[DRY RUN] device not attached", "protocol", dryRun.protocol, "volumeId", req.GetVolumeId(), "path", path)
+	return path, nil
+}
+
+func (dryRun *dryRunStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) error {
+	klog.InfoS("[DRY RUN] device not detached", "protocol", dryRun.protocol, "volumeId", req.GetVolumeId())
+	return nil
+}
+
+// NodePublishVolume mounts the volume on the node.
+func (dryRun *dryRunStorage) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "dry-run specific NodePublishVolume not implemented")
+}
+
+// NodeUnpublishVolume unmounts the volume from the target path
+func (dryRun *dryRunStorage) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "dry-run specific NodeUnpublishVolume not implemented")
+}
+
+// NodeGetVolumeStats return info about a given volume
+// Will not be called as the plugin does not have the GET_VOLUME_STATS capability
+func (dryRun *dryRunStorage) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetVolumeStats is not implemented")
+}
+
+// NodeExpandVolume reports the requested size without rescanning the device or growing the filesystem
+func (dryRun *dryRunStorage) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "node expand volume requires volume id")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "node expand volume requires volume path")
+	}
+	size := req.GetCapacityRange().GetRequiredBytes()
+	klog.InfoS("[DRY RUN] volume not expanded", "protocol", dryRun.protocol, "volumeId", req.GetVolumeId(), "size", size)
+	return &csi.NodeExpandVolumeResponse{CapacityBytes: size}, nil
+}
+
+// NodeGetCapabilities returns the supported capabilities of the node server
+func (dryRun *dryRunStorage) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetCapabilities is not implemented")
+}
+
+// NodeGetInfo returns info about the node
+func (dryRun *dryRunStorage) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetInfo is not implemented")
+}
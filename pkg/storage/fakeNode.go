@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// FakeInitiator is the initiator reported by the node when fake storage is in use
+const FakeInitiator = "iqn.2026-01.com.seagate:fake-node"
+
+// fakeStorage attaches a volume by creating a file named after its WWN instead of discovering a SCSI device
+type fakeStorage struct {
+	cs                commonService
+	connectorInfoPath string
+	dir               string
+}
+
+// fakeHost creates fake storage nodes backed by files in dir, with a single iSCSI initiator and no device monitors
+type fakeHost struct {
+	dir string
+}
+
+// fakeHostRunner answers host commands like a FakeRunner but creates and removes the mount target paths, which
+// tests expect to find on the filesystem
+type fakeHostRunner struct {
+	*FakeRunner
+	host ExecRunner
+}
+
+// UseFakeStorage: Make every storage protocol attach files created in dir, and answer host commands with a
+// FakeRunner so that the node plugin can run without root, an array or host binaries. Used by hermetic tests.
+func UseFakeStorage(dir string) *FakeRunner {
+	nodeStorage = &fakeHost{dir: dir}
+	// findmnt exits with 1 when nothing is mounted, which is always the case since mount is not run either
+	runner := NewFakeRunner().On("findmnt", "", 1)
+	SetCommandRunner(&fakeHostRunner{FakeRunner: runner})
+	klog.InfoS("using fake storage", "dir", dir)
+	return runner
+}
+
+func (host *fakeHost) newStorage(storageProtocol string, cs commonService, connectorInfoPath string) StorageOperations {
+	return &fakeStorage{cs: cs, connectorInfoPath: connectorInfoPath, dir: host.dir}
+}
+
+// initiators: Fake storage only ever reports an iSCSI initiator
+func (host *fakeHost) initiators(storageProtocol string) ([]string, error) {
+	if storageProtocol == common.StorageProtocolISCSI {
+		return []string{FakeInitiator}, nil
+	}
+	return []string{}, nil
+}
+
+// rescanLUN: Fake devices are created on attach, there is nothing to scan
+func (host *fakeHost) rescanLUN(storageProtocol string, lun int) ([]string, error) {
+	return []string{}, nil
+}
+
+// usedLUNs: Fake devices take no LUN
+func (host *fakeHost) usedLUNs(runPath string) (int, error) {
+	return 0, nil
+}
+
+// startMonitors: Fake devices are plain files, which report neither uevents, kernel errors nor multipath paths
+func (host *fakeHost) startMonitors(runPath string, expectedPaths map[string]int) {
+}
+
+func (runner *fakeHostRunner) Mkdir(path string, perm os.FileMode) error {
+	runner.FakeRunner.Mkdir(path, perm)
+	return runner.host.Mkdir(path, perm)
+}
+
+func (runner *fakeHostRunner) CreateFile(path string, perm os.FileMode) error {
+	runner.FakeRunner.CreateFile(path, perm)
+	return runner.host.CreateFile(path, perm)
+}
+
+func (runner *fakeHostRunner) Remove(path string) error {
+	runner.FakeRunner.Remove(path)
+	return runner.host.Remove(path)
+}
+
+// devicePath: Return the file standing in for the device of a volume
+func (fake *fakeStorage) devicePath(volumeId string) (string, error) {
+	wwn, err := common.VolumeIdGetWwn(volumeId)
+	if err != nil || wwn == "" {
+		return "", fmt.Errorf("no WWN found in volume id (%s)", volumeId)
+	}
+	return filepath.Join(fake.dir, wwn), nil
+}
+
+// NodeStageVolume mounts the volume to a staging path on the node.
+// Will not be called as the plugin does not have the STAGE_UNSTAGE_VOLUME capability
+func (fake *fakeStorage) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeStageVolume is not implemented")
+}
+
+// NodeUnstageVolume unstages the volume from the staging path
+// Will not be called as the plugin does not have the STAGE_UNSTAGE_VOLUME capability
+func (fake *fakeStorage) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeUnstageVolume is not implemented")
+}
+
+func (fake *fakeStorage) AttachStorage(ctx context.Context, req *csi.NodePublishVolumeRequest) (string, error) {
+	path, err := fake.devicePath(req.GetVolumeId())
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	file.Close()
+	klog.InfoS("attached fake device", "path", path)
+	return path, os.WriteFile(fake.connectorInfoPath, []byte(path), 0600)
+}
+
+func (fake *fakeStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) error {
+	path, err := os.ReadFile(fake.connectorInfoPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			klog.ErrorS(err, "assuming that fake device was already detached")
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	klog.InfoS("detaching fake device", "path", string(path))
+	os.Remove(string(path))
+	os.Remove(fake.connectorInfoPath)
+	return nil
+}
+
+// NodePublishVolume mounts the volume on the node.
+func (fake *fakeStorage) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "fake specific NodePublishVolume not implemented")
+}
+
+// NodeUnpublishVolume unmounts the volume from the target path
+func (fake *fakeStorage) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "fake specific NodeUnpublishVolume not implemented")
+}
+
+// NodeGetVolumeStats return info about a given volume
+// Will not be called as the plugin does not have the GET_VOLUME_STATS capability
+func (fake *fakeStorage) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetVolumeStats is not implemented")
+}
+
+// NodeExpandVolume grows the file of an attached volume to the requested size
+func (fake *fakeStorage) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
+	volumeName, _ := common.VolumeIdGetName(req.GetVolumeId())
+	if len(volumeName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "node expand volume requires volume id")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "node expand volume requires volume path")
+	}
+
+	path, err := os.ReadFile(fake.connectorInfoPath)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("node expand volume path not found for volume id (%s)", volumeName))
+	}
+
+	size := req.GetCapacityRange().GetRequiredBytes()
+	if size > 0 {
+		if err := os.Truncate(string(path), size); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	klog.InfoS("expanded fake device", "path", string(path), "size", size)
+	return &csi.NodeExpandVolumeResponse{CapacityBytes: size}, nil
+}
+
+// NodeGetCapabilities returns the supported capabilities of the node server
+func (fake *fakeStorage) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetCapabilities is not implemented")
+}
+
+// NodeGetInfo returns info about the node
+func (fake *fakeStorage) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "NodeGetInfo is not implemented")
+}
@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/exporter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// Filesystem check results
+const (
+	FsCheckResultSkipped   = "skipped"
+	FsCheckResultClean     = "clean"
+	FsCheckResultRepaired  = "repaired"
+	FsCheckResultCorrupted = "corrupted"
+	FsCheckResultFailed    = "failed"
+)
+
+// FsCheckMetrics counts filesystem check results, it is registered with the node exporter
+var FsCheckMetrics = exporter.NewFsCheckCollector()
+
+// FsCheckRecord is the structured result of the last filesystem check of a volume
+type FsCheckRecord struct {
+	VolumeId  string    `json:"volumeId"`
+	Device    string    `json:"device"`
+	FsType    string    `json:"fsType"`
+	Policy    string    `json:"policy"`
+	Context   string    `json:"context"`
+	Result    string    `json:"result"`
+	Output    string    `json:"output,omitempty"`
+	Debug     string    `json:"debug,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetFsCheckPolicy: Return the filesystem check policy from the volume context (StorageClass parameters)
+func GetFsCheckPolicy(volumeContext map[string]string) (string, error) {
+	policy := volumeContext[common.FsCheckPolicyConfigKey]
+	switch policy {
+	case "":
+		return common.FsCheckPolicyCheck, nil
+	case common.FsCheckPolicySkip, common.FsCheckPolicyCheck, common.FsCheckPolicyRepair:
+		return policy, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "parameter %s must be one of %s, %s or %s, got %q", common.FsCheckPolicyConfigKey,
+		common.FsCheckPolicySkip, common.FsCheckPolicyCheck, common.FsCheckPolicyRepair, policy)
+}
+
+// GetFsCheckRecordPath: Return the path of the filesystem check record of a volume
+func GetFsCheckRecordPath(runPath string, volumeId string) string {
+	volumeName, _ := common.VolumeIdGetName(volumeId)
+	return filepath.Join(runPath, fmt.Sprintf("fscheck-%s.json", volumeName))
+}
+
+// Save: Write the record as JSON under the run path with the configured runner, replacing any previous record of the volume
+func (record *FsCheckRecord) Save(runPath string) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return commandRunner.WriteFile(GetFsCheckRecordPath(runPath, record.VolumeId), string(data))
+}
+
+// RemoveFsCheckRecord: Remove the filesystem check record of a volume detached from the node
+func RemoveFsCheckRecord(runPath string, volumeId string) {
+	recordPath := GetFsCheckRecordPath(runPath, volumeId)
+	if err := commandRunner.Remove(recordPath); err != nil && !os.IsNotExist(err) {
+		klog.ErrorS(err, "could not remove filesystem check record", "path", recordPath)
+	}
+}
+
+// fsCheckCommand: Return the command checking (repair == false) or repairing a filesystem
+func fsCheckCommand(path string, fsType string, repair bool) *Command {
+	switch fsType {
+	case "xfs":
+		if repair {
+			return NewCommand("xfs_repair", path)
+		}
+		return NewCommand("xfs_repair", "-n", path)
+	case "btrfs":
+		// btrfs check --repair is not safe to run unattended, so btrfs is only ever checked
+		return NewCommand("btrfs", "check", "--readonly", path)
+	default:
+		if repair {
+			return NewCommand("e2fsck", "-p", path)
+		}
+		return NewCommand("e2fsck", "-n", path)
+	}
+}
+
+// runFsCheck: Run a filesystem check or repair and classify its result
+func runFsCheck(path string, fsType string, repair bool) (string, string) {
+	cmd := fsCheckCommand(path, fsType, repair)
+	klog.InfoS("checking filesystem", "command", cmd.String())
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		if repair {
+			return FsCheckResultRepaired, string(out)
+		}
+		return FsCheckResultClean, string(out)
+	}
+	exitCode, ok := ExitCode(err)
+	if !ok {
+		return FsCheckResultFailed, fmt.Sprintf("%s (%v)", out, err)
+	}
+	if fsType != "xfs" && fsType != "btrfs" {
+		// e2fsck exits with 1 or 2 when errors were corrected, 4 when errors were left uncorrected, and with 8 or
+		// more when it could not check the filesystem at all (operational error, usage error, cancelled...)
+		if exitCode >= 8 {
+			return FsCheckResultFailed, fmt.Sprintf("%s (%v)", out, err)
+		}
+		if repair && (exitCode == 1 || exitCode == 2) {
+			return FsCheckResultRepaired, string(out)
+		}
+	}
+	return FsCheckResultCorrupted, string(out)
+}
+
+// CheckFs: Perform a file system validation, and a repair if the policy allows it and the volume is published
+// read-write. The result is recorded in the fs check metric and in a JSON record under runPath. A check which could not
+// be run fails the publish, as nothing is known about the filesystem.
+func CheckFs(volumeId string, path string, fsType string, policy string, readOnly bool, context string, runPath string) error {
+
+	if IsVolumeInUse(path) {
+		klog.Infof("Volume already mounted, not performing FS check")
+		return nil
+	}
+
+	record := &FsCheckRecord{
+		VolumeId:  volumeId,
+		Device:    path,
+		FsType:    fsType,
+		Policy:    policy,
+		Context:   context,
+		Result:    FsCheckResultSkipped,
+		Timestamp: time.Now(),
+	}
+
+	if policy != common.FsCheckPolicySkip {
+		record.Result, record.Output = runFsCheck(path, fsType, false)
+		// a read-only volume may be mounted by other nodes at the same time, so it is never written to
+		if record.Result == FsCheckResultCorrupted && policy == common.FsCheckPolicyRepair && readOnly {
+			klog.InfoS("filesystem check found errors, not repairing a read-only volume", "device", path, "fsType", fsType)
+		} else if record.Result == FsCheckResultCorrupted && policy == common.FsCheckPolicyRepair && fsType != "btrfs" {
+			klog.InfoS("filesystem check found errors, repairing", "device", path, "fsType", fsType)
+			record.Result, record.Output = runFsCheck(path, fsType, true)
+		}
+		if record.Result == FsCheckResultCorrupted {
+			record.Debug = DebugCorruption(fmt.Sprintf("[%s]", context), path)
+		}
+	}
+
+	klog.InfoS("filesystem check result", "device", path, "fsType", fsType, "policy", policy, "result", record.Result)
+	FsCheckMetrics.IncFsCheck(fsType, policy, record.Result)
+	recordPath := GetFsCheckRecordPath(runPath, volumeId)
+	if err := record.Save(runPath); err != nil {
+		klog.ErrorS(err, "could not save filesystem check record", "path", recordPath)
+	}
+
+	switch record.Result {
+	case FsCheckResultCorrupted:
+		return status.Errorf(codes.FailedPrecondition, "filesystem check found errors on %s (policy %s), see %s: %s", path, policy, recordPath, record.Output)
+	case FsCheckResultFailed:
+		return status.Errorf(codes.Internal, "filesystem check could not be run on %s (policy %s), see %s: %s", path, policy, recordPath, record.Output)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Types of the device health events reported by the node plugin
+const (
+	HealthPathFailed      = "PathFailed"
+	HealthPathRestored    = "PathRestored"
+	HealthReadOnlyRemount = "ReadOnlyRemount"
+	HealthSCSIError       = "SCSIError"
+	HealthPathSnapshot    = "PathSnapshot"
+)
+
+// Severities of the device health events
+const (
+	HealthSeverityInfo    = "Info"
+	HealthSeverityWarning = "Warning"
+	HealthSeverityError   = "Error"
+)
+
+const (
+	kernelLogPath = "/dev/kmsg"
+	// healthThrottle: Report repeated kernel errors of a device at most once per interval
+	healthThrottle = 10 * time.Second
+)
+
+var (
+	readOnlyPattern  = regexp.MustCompile(`(?:EXT4-fs(?: error)?|XFS) \((?:device )?([\w-]+)\).*(?:[Rr]emounting filesystem read-only|[Ss]hutting down filesystem|[Ff]ilesystem has been shut down)`)
+	scsiErrorPattern = regexp.MustCompile(`(?:I/O error, dev ([\w-]+),|sd \S+: \[([\w-]+)\] .*(?:FAILED Result|Sense Key))`)
+)
+
+// HealthEvent is a problem with a volume device, or its recovery, seen on the node
+type HealthEvent struct {
+	Type      string
+	Severity  string
+	WWN       string
+	Path      string
+	Message   string
+	Timestamp time.Time
+	// FailedPaths lists every failed path of the device in a path snapshot
+	FailedPaths []string
+}
+
+type healthBroadcaster struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]chan HealthEvent
+}
+
+var healthEvents = &healthBroadcaster{subscribers: map[int]chan HealthEvent{}}
+
+// SubscribeHealthEvents: Receive the health events of the node until the returned function is called
+func SubscribeHealthEvents() (<-chan HealthEvent, func()) {
+	healthEvents.mu.Lock()
+	defer healthEvents.mu.Unlock()
+	id := healthEvents.nextID
+	healthEvents.nextID++
+	events := make(chan HealthEvent, 64)
+	healthEvents.subscribers[id] = events
+	return events, func() {
+		healthEvents.mu.Lock()
+		defer healthEvents.mu.Unlock()
+		delete(healthEvents.subscribers, id)
+	}
+}
+
+// HealthSnapshot: Return the current failed paths of every multipath device of the node, one path snapshot per WWN, so
+// that a new subscriber does not depend on the events it missed
+func HealthSnapshot() ([]HealthEvent, error) {
+	out, err := showMultipathPaths()
+	if err != nil {
+		return nil, err
+	}
+	failed := failedMultipathPaths(string(out))
+	wwns := make([]string, 0, len(failed))
+	for wwn := range failed {
+		wwns = append(wwns, wwn)
+	}
+	sort.Strings(wwns)
+	snapshot := []HealthEvent{}
+	now := time.Now()
+	for _, wwn := range wwns {
+		severity := HealthSeverityInfo
+		if len(failed[wwn]) > 0 {
+			severity = HealthSeverityWarning
+		}
+		snapshot = append(snapshot, HealthEvent{
+			Type:        HealthPathSnapshot,
+			Severity:    severity,
+			WWN:         wwn,
+			Message:     fmt.Sprintf("%d failed paths", len(failed[wwn])),
+			Timestamp:   now,
+			FailedPaths: failed[wwn],
+		})
+	}
+	return snapshot, nil
+}
+
+// publishHealthEvent: Hand an event to every subscriber, dropping it for subscribers which fell behind
+func publishHealthEvent(event HealthEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	klog.InfoS("device health event", "type", event.Type, "severity", event.Severity, "wwn", event.WWN, "path", event.Path, "message", event.Message)
+	healthEvents.mu.Lock()
+	defer healthEvents.mu.Unlock()
+	for _, subscriber := range healthEvents.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			klog.V(2).InfoS("health event dropped, subscriber is not keeping up", "type", event.Type, "wwn", event.WWN)
+		}
+	}
+}
+
+// multipathHealthEvent: Turn the path failure and reinstatement uevents of a multipath map into a health event
+func multipathHealthEvent(event Uevent) (HealthEvent, bool) {
+	path := blockDeviceName(event.DMPath)
+	switch event.DMAction {
+	case "PATH_FAILED":
+		severity := HealthSeverityWarning
+		if event.DMValidPaths == "0" {
+			severity = HealthSeverityError
+		}
+		return HealthEvent{
+			Type:     HealthPathFailed,
+			Severity: severity,
+			WWN:      event.WWN,
+			Path:     path,
+			Message:  "path " + path + " failed, " + event.DMValidPaths + " valid paths remaining",
+		}, true
+	case "PATH_REINSTATED":
+		return HealthEvent{
+			Type:     HealthPathRestored,
+			Severity: HealthSeverityInfo,
+			WWN:      event.WWN,
+			Path:     path,
+			Message:  "path " + path + " reinstated, " + event.DMValidPaths + " valid paths",
+		}, true
+	}
+	return HealthEvent{}, false
+}
+
+// blockDeviceName: Return the name of a block device from its "major:minor" number
+func blockDeviceName(majorMinor string) string {
+	if majorMinor == "" {
+		return ""
+	}
+	if target, err := os.Readlink(filepath.Join(sysfsPath, "dev", "block", majorMinor)); err == nil {
+		return filepath.Base(target)
+	}
+	return majorMinor
+}
+
+// kernelLogHealthEvent: Recognize read-only remounts and SCSI errors in a kernel log message
+func kernelLogHealthEvent(message string) (HealthEvent, bool) {
+	if match := readOnlyPattern.FindStringSubmatch(message); match != nil {
+		return HealthEvent{Type: HealthReadOnlyRemount, Severity: HealthSeverityError, Path: match[1], Message: message}, true
+	}
+	if match := scsiErrorPattern.FindStringSubmatch(message); match != nil {
+		device := match[1]
+		if device == "" {
+			device = match[2]
+		}
+		return HealthEvent{Type: HealthSCSIError, Severity: HealthSeverityWarning, Path: device, Message: message}, true
+	}
+	return HealthEvent{}, false
+}
+
+// StartHealthMonitor: Follow the kernel log for filesystem and SCSI errors on volume devices. Multipath path events
+// are reported by the device watcher.
+func StartHealthMonitor() error {
+	kernelLog, err := os.Open(kernelLogPath)
+	if err != nil {
+		return err
+	}
+	// only report what happens from now on
+	if _, err := kernelLog.Seek(0, io.SeekEnd); err != nil {
+		kernelLog.Close()
+		return err
+	}
+	go followKernelLog(kernelLog)
+	return nil
+}
+
+func followKernelLog(kernelLog *os.File) {
+	defer kernelLog.Close()
+	reported := map[string]time.Time{}
+	buffer := make([]byte, 8192)
+	for {
+		n, err := kernelLog.Read(buffer)
+		if err != nil {
+			if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.EINTR) {
+				// records were overwritten before being read
+				continue
+			}
+			klog.ErrorS(err, "unable to read the kernel log, device errors are no longer reported")
+			return
+		}
+		// records are "priority,sequence,timestamp,flags;message"
+		_, message, found := strings.Cut(strings.SplitN(string(buffer[:n]), "\n", 2)[0], ";")
+		if !found {
+			continue
+		}
+		event, ok := kernelLogHealthEvent(message)
+		if !ok {
+			continue
+		}
+		event.WWN = sysfsWWN(filepath.Join("block", event.Path))
+		if event.WWN == "" {
+			continue
+		}
+		key := event.Type + "/" + event.Path
+		if time.Since(reported[key]) < healthThrottle {
+			continue
+		}
+		reported[key] = time.Now()
+		publishHealthEvent(event)
+	}
+}
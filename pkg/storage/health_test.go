@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHealthEvents(t *testing.T) {
+	g := NewWithT(t)
+
+	sysfs := t.TempDir()
+	sysfsPath = sysfs
+	t.Cleanup(func() { sysfsPath = "/sys" })
+	g.Expect(os.MkdirAll(filepath.Join(sysfs, "dev", "block"), 0755)).To(Succeed())
+	g.Expect(os.Symlink("../../devices/platform/host3/target3:0:0/3:0:0:1/block/sdc", filepath.Join(sysfs, "dev", "block", "8:32"))).To(Succeed())
+
+	events, unsubscribe := SubscribeHealthEvents()
+	defer unsubscribe()
+	watcher := newDeviceWatcher()
+	failed := Uevent{Action: "change", DevPath: "/devices/virtual/block/dm-2", WWN: testWWN, Seqnum: "4242",
+		DMAction: "PATH_FAILED", DMPath: "8:32", DMValidPaths: "1"}
+	watcher.dispatch(failed)
+	// the same event is received from the kernel and from udev
+	watcher.dispatch(failed)
+	watcher.dispatch(Uevent{Action: "change", DevPath: "/devices/virtual/block/dm-2", WWN: testWWN, Seqnum: "4243",
+		DMAction: "PATH_REINSTATED", DMPath: "8:32", DMValidPaths: "2"})
+
+	event := <-events
+	g.Expect(event.Type).To(Equal(HealthPathFailed))
+	g.Expect(event.Severity).To(Equal(HealthSeverityWarning))
+	g.Expect(event.WWN).To(Equal(testWWN))
+	g.Expect(event.Path).To(Equal("sdc"))
+	g.Expect(event.Message).To(Equal("path sdc failed, 1 valid paths remaining"))
+	g.Expect(event.Timestamp).To(BeTemporally("~", time.Now(), time.Second))
+	g.Expect((<-events).Type).To(Equal(HealthPathRestored))
+	g.Expect(events).To(BeEmpty())
+
+	event, ok := kernelLogHealthEvent("EXT4-fs (dm-3): Remounting filesystem read-only")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.Type).To(Equal(HealthReadOnlyRemount))
+	g.Expect(event.Path).To(Equal("dm-3"))
+	event, ok = kernelLogHealthEvent("XFS (dm-4): Corruption of in-memory data detected.  Shutting down filesystem")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.Type).To(Equal(HealthReadOnlyRemount))
+	event, ok = kernelLogHealthEvent("I/O error, dev sdc, sector 2048 op 0x0:(READ) flags 0x0 phys_seg 1 prio class 2")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.Type).To(Equal(HealthSCSIError))
+	g.Expect(event.Path).To(Equal("sdc"))
+	event, ok = kernelLogHealthEvent("sd 3:0:0:1: [sdd] tag#12 FAILED Result: hostbyte=DID_TRANSPORT_DISRUPTED driverbyte=DRIVER_OK")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.Path).To(Equal("sdd"))
+	_, ok = kernelLogHealthEvent("EXT4-fs (dm-3): mounted filesystem with ordered data mode")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestHealthSnapshot(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	runner.On("multipathd show paths", `uuid                              dev dm_st  chk_st
+3600c0ff0005149ed8e2c7c6501000000 sdb active ready
+3600c0ff0005149ed8e2c7c6501000000 sdc failed faulty
+3600c0ff0005149ed8e2c7c6501000000 sdd active i/o pending
+3600c0ff0005149ed8e2c7c6502000000 sde active ready
+`, 0)
+	snapshot, err := HealthSnapshot()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshot).To(HaveLen(2))
+	g.Expect(snapshot[0]).To(And(HaveField("Type", HealthPathSnapshot), HaveField("Severity", HealthSeverityWarning),
+		HaveField("WWN", "600c0ff0005149ed8e2c7c6501000000"), HaveField("FailedPaths", []string{"sdc"})))
+	// devices without failed paths are part of the snapshot, so that failures the subscriber knew of are cleared
+	g.Expect(snapshot[1]).To(And(HaveField("Type", HealthPathSnapshot), HaveField("Severity", HealthSeverityInfo),
+		HaveField("WWN", "600c0ff0005149ed8e2c7c6502000000"), HaveField("FailedPaths", BeEmpty())))
+}
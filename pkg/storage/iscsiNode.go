@@ -23,10 +23,8 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
-	"time"
 
 	iscsilib "github.com/Seagate/csi-lib-iscsi/iscsi"
 	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
@@ -127,30 +125,9 @@ func (iscsi *iscsiStorage) AttachStorage(ctx context.Context, req *csi.NodePubli
 	}
 	klog.InfoS("attached device:", "path", path)
 
-	exists := true
-	out, err := exec.Command("ls", "-l", fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)).CombinedOutput()
-	klog.V(1).InfoS("ls command output", "command", fmt.Sprintf("ls -l /dev/disk/by-id/dm-name-3%s", wwn), "err", err, "out", out)
+	err = WaitForMultipathDevice(ctx, wwn)
 	if err != nil {
-		exists = false
-	}
-
-	// wait here until the dm-name exists, for debugging
-	if !exists {
-		attempts := 1
-		for attempts < (maxDmnameAttempts + 1) {
-			// Force a reload of all existing multipath maps
-			output, err := exec.Command("multipath", "-r").CombinedOutput()
-			klog.V(4).InfoS("## (publish) multipath -r output", "err", err, "output", output)
-
-			out, err := exec.Command("ls", "-l", fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)).CombinedOutput()
-			klog.V(1).InfoS("check for dm-name exists", "attempt", attempts, "command", fmt.Sprintf("ls -l /dev/disk/by-id/dm-name-3%s", wwn), "err", err, "out", out)
-			if err == nil {
-				exists = true
-				break
-			}
-			time.Sleep(dmnameDelay * time.Second)
-			attempts++
-		}
+		klog.InfoS("multipath device not found, using the attached device", "path", path, "err", err)
 	}
 	if _, err := os.Stat(iscsi.connectorInfoPath); err == nil {
 		klog.InfoS("iscsi connection file already exists", "connectorInfoPath", iscsi.connectorInfoPath)
@@ -180,11 +157,15 @@ func (iscsi *iscsiStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpub
 	}
 	klog.InfoS("connector.DevicePath", "connector.DevicePath", connector.DevicePath)
 
-	if IsVolumeInUse(connector.DevicePath) {
+	if IsVolumeInUse(connector.DevicePath) || IsEncryptedDeviceInUse(req.GetVolumeId()) {
 		klog.Info("volume is still in use on the node, thus it will not be detached")
 		return nil
 	}
 
+	if err := CloseEncryptedDevice(req.GetVolumeId()); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
 	_, err = os.Stat(connector.DevicePath)
 	if err != nil && os.IsNotExist(err) {
 		klog.InfoS("connector.devicePath does not exist, assuming that volume is already disconnected")
@@ -192,14 +173,18 @@ func (iscsi *iscsiStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpub
 	}
 
 	wwn, _ := common.VolumeIdGetWwn(req.GetVolumeId())
-	out, err := exec.Command("ls", "-l", fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)).CombinedOutput()
-	klog.Infof("check for dm-name: ls -l %s, err = %v, out = \n%s", fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn), err, string(out))
+	dmName := fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)
+	out, err := NewCommand("ls", "-l", dmName).CombinedOutput()
+	klog.Infof("check for dm-name: ls -l %s, err = %v, out = \n%s", dmName, err, string(out))
 
 	klog.Info("DisconnectVolume, detaching ISCSI device")
 	err = iscsilib.DisconnectVolume(*connector)
 	if err != nil {
 		return err
 	}
+	if err := WaitForDeviceRemoval(ctx, wwn, connector.DevicePath, dmName); err != nil {
+		klog.ErrorS(err, "device still present after detach", "device", connector.DevicePath)
+	}
 
 	klog.Infof("deleting ISCSI connection info file %s", iscsi.connectorInfoPath)
 	os.Remove(iscsi.connectorInfoPath)
@@ -249,19 +234,13 @@ func (iscsi *iscsiStorage) NodeExpandVolume(ctx context.Context, req *csi.NodeEx
 			return nil, err
 		}
 	} else {
-		klog.V(2).Info("device is NOT using multipath")
-	}
-
-	if req.GetVolumeCapability().GetMount() != nil {
-		klog.Infof("expanding filesystem using resize2fs on device %s", connector.DevicePath)
-		output, err := exec.Command("resize2fs", connector.DevicePath).CombinedOutput()
-		if err != nil {
-			klog.V(2).InfoS("could not resize filesystem", "resize2fs output", output)
-			return nil, fmt.Errorf("could not resize filesystem: %v", output)
+		klog.V(2).Info("device is NOT using multipath, rescanning iSCSI sessions")
+		if err := rescanISCSISessions(connector); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
-	return &csi.NodeExpandVolumeResponse{}, nil
+	return NodeExpandFilesystem(ctx, req, connector.DevicePath)
 }
 
 // NodeGetCapabilities returns the supported capabilities of the node server
@@ -274,6 +253,18 @@ func (iscsi *iscsiStorage) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfo
 	return nil, status.Error(codes.Unimplemented, "NodeGetInfo is not implemented")
 }
 
+// rescanISCSISessions: Rescan the iSCSI sessions of a connector so the kernel picks up the new LUN capacity
+func rescanISCSISessions(connector *iscsilib.Connector) error {
+	for _, target := range connector.Targets {
+		klog.V(2).InfoS("rescanning iSCSI session", "iqn", target.Iqn, "portal", target.Portal)
+		output, err := NewCommand("iscsiadm", "-m", "node", "-T", target.Iqn, "-p", target.Portal, "-R").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("could not rescan iSCSI session (%s, %s): %s", target.Iqn, target.Portal, output)
+		}
+	}
+	return nil
+}
+
 func GetISCSIInitiators() ([]string, error) {
 	initiatorNameFilePath := "/etc/iscsi/initiatorname.iscsi"
 	file, err := os.Open(initiatorNameFilePath)
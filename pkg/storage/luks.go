@@ -0,0 +1,133 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const luksMapperPrefix = "exos-luks"
+
+// IsEncrypted: Return true if the volume context (StorageClass parameters) requests LUKS encryption
+func IsEncrypted(volumeContext map[string]string) (bool, error) {
+	value, ok := volumeContext[common.EncryptedConfigKey]
+	if !ok || value == "" {
+		return false, nil
+	}
+	encrypted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "parameter %s must be true or false, got %q", common.EncryptedConfigKey, value)
+	}
+	return encrypted, nil
+}
+
+// luksMapperName: Return the device mapper name used for the LUKS mapping of a volume
+func luksMapperName(volumeId string) string {
+	volumeName, _ := common.VolumeIdGetName(volumeId)
+	return fmt.Sprintf("%s-%s", luksMapperPrefix, volumeName)
+}
+
+// GetLuksMapperPath: Return the path of the open LUKS mapping of a volume, and whether it exists
+func GetLuksMapperPath(volumeId string) (string, bool) {
+	mapperPath := "/dev/mapper/" + luksMapperName(volumeId)
+	_, err := os.Stat(mapperPath)
+	return mapperPath, err == nil
+}
+
+// runCryptsetup: Run a cryptsetup command, passing the passphrase on stdin when one is given
+func runCryptsetup(passphrase string, args ...string) error {
+	if passphrase != "" {
+		args = append(args, "--key-file=-")
+	}
+	cmd := NewCommand("cryptsetup", args...)
+	cmd.Stdin = passphrase
+	klog.V(2).InfoS("cryptsetup", "command", cmd.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cryptsetup %s failed: %s (%v)", args[0], out, err)
+	}
+	return nil
+}
+
+// OpenEncryptedDevice: Format the device with LUKS if it is blank, open the LUKS mapping and return the mapper device path
+func OpenEncryptedDevice(devicePath string, volumeId string, secrets map[string]string) (string, error) {
+	mapperPath, exists := GetLuksMapperPath(volumeId)
+	if exists {
+		klog.InfoS("LUKS mapping already open", "device", devicePath, "mapperPath", mapperPath)
+		return mapperPath, nil
+	}
+
+	passphrase := secrets[common.EncryptionPassphraseKey]
+	if passphrase == "" {
+		return "", status.Errorf(codes.InvalidArgument, "(%s) is missing from node publish secrets", common.EncryptionPassphraseKey)
+	}
+
+	currentFormat, err := FindDeviceFormat(devicePath)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	switch currentFormat {
+	case "crypto_LUKS":
+	case "":
+		klog.InfoS("formatting device with LUKS", "device", devicePath)
+		if err := runCryptsetup(passphrase, "luksFormat", "--batch-mode", "--type", "luks2", devicePath); err != nil {
+			return "", status.Error(codes.Internal, err.Error())
+		}
+	default:
+		return "", status.Errorf(codes.FailedPrecondition, "could not encrypt device %s since it already has a %s format", devicePath, currentFormat)
+	}
+
+	klog.InfoS("opening LUKS device", "device", devicePath, "mapperPath", mapperPath)
+	if err := runCryptsetup(passphrase, "luksOpen", devicePath, luksMapperName(volumeId)); err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	return mapperPath, nil
+}
+
+// IsEncryptedDeviceInUse: Return true if the LUKS mapping of a volume is still mounted
+func IsEncryptedDeviceInUse(volumeId string) bool {
+	mapperPath, exists := GetLuksMapperPath(volumeId)
+	return exists && IsVolumeInUse(mapperPath)
+}
+
+// CloseEncryptedDevice: Close the LUKS mapping of a volume, if there is one, before its device is detached
+func CloseEncryptedDevice(volumeId string) error {
+	mapperPath, exists := GetLuksMapperPath(volumeId)
+	if !exists {
+		return nil
+	}
+	klog.InfoS("closing LUKS device", "mapperPath", mapperPath)
+	return runCryptsetup("", "luksClose", luksMapperName(volumeId))
+}
+
+// ResizeEncryptedDevice: Grow the LUKS mapping of a volume to fill its underlying device, the passphrase comes from the
+// node-expand secret of the StorageClass
+func ResizeEncryptedDevice(volumeId string, secrets map[string]string) error {
+	if secrets[common.EncryptionPassphraseKey] == "" {
+		return status.Errorf(codes.InvalidArgument, "(%s) is missing from node expand secrets, set csi.storage.k8s.io/node-expand-secret-name in the StorageClass", common.EncryptionPassphraseKey)
+	}
+	klog.InfoS("resizing LUKS device", "mapperName", luksMapperName(volumeId))
+	return runCryptsetup(secrets[common.EncryptionPassphraseKey], "resize", luksMapperName(volumeId))
+}
@@ -0,0 +1,235 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/exporter"
+	"k8s.io/klog/v2"
+)
+
+// MultipathMetrics exports the path counts of the attached volumes, it is registered with the node exporter
+var MultipathMetrics = exporter.NewMultipathCollector()
+
+// multipathPollInterval: How often the paths of the attached volumes are checked
+const multipathPollInterval = time.Minute
+
+// VolumePaths is the state of the paths of a volume attached to the node
+type VolumePaths struct {
+	VolumeName string
+	Protocol   string
+	WWN        string
+	Active     int
+	Failed     int
+	Ghost      int
+	// Expected is the number of paths configured for the protocol of the volume, 0 when not configured
+	Expected int
+}
+
+// Degraded: Return true when the volume has fewer active paths than expected for its protocol, or none at all
+func (paths *VolumePaths) Degraded() bool {
+	if paths.Expected == 0 {
+		return paths.Active == 0
+	}
+	return paths.Active < paths.Expected
+}
+
+// connectorDevice: the fields of the iSCSI, FC and SAS connector files which identify the device of a volume
+type connectorDevice struct {
+	Multipath bool `json:"multipath"`
+	// FC and SAS: the WWN the volume was attached by
+	VolumeWWN string `json:"volume_wwn"`
+	// iSCSI: the multipath device, whose WWN is read from sysfs
+	DevicePath string `json:"device_path"`
+}
+
+// ConnectorInfoPath: Return the file the connector of a volume attached with a storage protocol is saved to
+func ConnectorInfoPath(runPath string, storageProtocol string, volumeName string) string {
+	return fmt.Sprintf("%s/%s-%s.json", runPath, storageProtocol, volumeName)
+}
+
+// attachedVolumes: Return the multipath volumes attached to the node from their connector files, along with the number
+// of paths expected for their protocol
+func attachedVolumes(runPath string, expectedPaths map[string]int) []*VolumePaths {
+	volumes := []*VolumePaths{}
+	for _, protocol := range []string{common.StorageProtocolISCSI, common.StorageProtocolFC, common.StorageProtocolSAS} {
+		files, _ := filepath.Glob(ConnectorInfoPath(runPath, protocol, "*"))
+		for _, file := range files {
+			volumeName := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), protocol+"-"), ".json")
+			connector := connectorDevice{}
+			data, err := os.ReadFile(file)
+			if err == nil {
+				err = json.Unmarshal(data, &connector)
+			}
+			if err != nil || !connector.Multipath {
+				continue
+			}
+			wwn := connector.VolumeWWN
+			if wwn == "" && connector.DevicePath != "" {
+				wwn = sysfsWWN(filepath.Join("block", filepath.Base(connector.DevicePath)))
+			}
+			if wwn == "" {
+				klog.V(3).InfoS("unable to tell the WWN of an attached volume", "volume", volumeName, "connector", file)
+				continue
+			}
+			volumes = append(volumes, &VolumePaths{
+				VolumeName: volumeName,
+				Protocol:   protocol,
+				WWN:        strings.ToLower(wwn),
+				Expected:   expectedPaths[protocol],
+			})
+		}
+	}
+	return volumes
+}
+
+// countMultipathPaths: Count the paths of the attached volumes in the output of multipathd show paths
+func countMultipathPaths(volumes []*VolumePaths, output string) {
+	byWWN := map[string]*VolumePaths{}
+	for _, volume := range volumes {
+		byWWN[volume.WWN] = volume
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		volume := byWWN[strings.ToLower(strings.TrimPrefix(fields[0], "3"))]
+		if volume == nil {
+			continue
+		}
+		switch multipathPathState(fields) {
+		case "ghost":
+			volume.Ghost++
+		case "failed":
+			volume.Failed++
+		case "active":
+			volume.Active++
+		}
+	}
+}
+
+// multipathPathState: Classify a path of multipathd show paths from its device mapper and checker states as active,
+// failed, ghost or "" when in between
+func multipathPathState(fields []string) string {
+	// uuid dev dm_st chk_st, the checker state may hold a space ("i/o pending")
+	dmState, checkerState := fields[2], strings.Join(fields[3:], " ")
+	switch {
+	case checkerState == "ghost":
+		return "ghost"
+	case dmState == "failed" || checkerState == "faulty" || checkerState == "shaky":
+		return "failed"
+	case dmState == "active" && checkerState == "ready":
+		return "active"
+	}
+	return ""
+}
+
+// failedMultipathPaths: Return the failed paths of every multipath map of the node by WWN, from the output of
+// multipathd show paths. Maps without failed paths are listed with none.
+func failedMultipathPaths(output string) map[string][]string {
+	failed := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "3") {
+			continue
+		}
+		wwn := strings.ToLower(strings.TrimPrefix(fields[0], "3"))
+		if _, ok := failed[wwn]; !ok {
+			failed[wwn] = []string{}
+		}
+		if multipathPathState(fields) == "failed" {
+			failed[wwn] = append(failed[wwn], fields[1])
+		}
+	}
+	return failed
+}
+
+type multipathMonitor struct {
+	mu            sync.Mutex
+	runPath       string
+	expectedPaths map[string]int
+	exported      map[string]string
+	degraded      map[string]bool
+}
+
+// check: Count the active, failed and ghost paths of the volumes attached to the node, export them and
+// warn about volumes running on fewer paths than expected
+func (monitor *multipathMonitor) check() ([]*VolumePaths, error) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	volumes := attachedVolumes(monitor.runPath, monitor.expectedPaths)
+	if len(volumes) > 0 {
+		out, err := showMultipathPaths()
+		if err != nil {
+			return nil, err
+		}
+		countMultipathPaths(volumes, string(out))
+	}
+
+	attached := map[string]bool{}
+	for _, volume := range volumes {
+		attached[volume.VolumeName] = true
+		monitor.exported[volume.VolumeName] = volume.Protocol
+		MultipathMetrics.SetVolumePaths(volume.VolumeName, volume.Protocol, volume.Active, volume.Failed, volume.Ghost, volume.Expected)
+		degraded := volume.Degraded()
+		if degraded && !monitor.degraded[volume.VolumeName] {
+			klog.Warningf("volume %s (%s) is running on %d of %d paths (%d failed, %d ghost)", volume.VolumeName, volume.Protocol,
+				volume.Active, volume.Expected, volume.Failed, volume.Ghost)
+		} else if !degraded && monitor.degraded[volume.VolumeName] {
+			klog.InfoS("volume paths restored", "volume", volume.VolumeName, "active", volume.Active, "expected", volume.Expected)
+		}
+		monitor.degraded[volume.VolumeName] = degraded
+	}
+	for volumeName, protocol := range monitor.exported {
+		if !attached[volumeName] {
+			MultipathMetrics.DeleteVolume(volumeName, protocol)
+			delete(monitor.exported, volumeName)
+			delete(monitor.degraded, volumeName)
+		}
+	}
+	return volumes, nil
+}
+
+// showMultipathPaths: Return the WWN, device, device mapper state and checker state of every multipath path
+func showMultipathPaths() ([]byte, error) {
+	return NewCommand("multipathd", "show", "paths", "format", "%w %d %t %T").CombinedOutput()
+}
+
+// StartMultipathMonitor: Periodically check the paths of the volumes attached to the node against the number of paths
+// expected for each protocol
+func StartMultipathMonitor(runPath string, expectedPaths map[string]int) {
+	monitor := &multipathMonitor{runPath: runPath, expectedPaths: expectedPaths, exported: map[string]string{}, degraded: map[string]bool{}}
+	go func() {
+		for {
+			if _, err := monitor.check(); err != nil {
+				klog.ErrorS(err, "unable to check multipath paths")
+			}
+			time.Sleep(multipathPollInterval)
+		}
+	}()
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckMultipathPaths(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	runPath := t.TempDir()
+
+	sysfs := t.TempDir()
+	sysfsPath = sysfs
+	t.Cleanup(func() { sysfsPath = "/sys" })
+	g.Expect(os.MkdirAll(filepath.Join(sysfs, "block", "dm-2", "dm"), 0755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(sysfs, "block", "dm-2", "dm", "uuid"), []byte("mpath-3600c0ff0005149ed8e2c7c6501000000\n"), 0644)).To(Succeed())
+
+	writeConnector := func(protocol string, volumeName string, content string) {
+		g.Expect(os.WriteFile(ConnectorInfoPath(runPath, protocol, volumeName), []byte(content), 0644)).To(Succeed())
+	}
+	// the WWN of iSCSI volumes comes from their multipath device, FC and SAS connectors hold it
+	writeConnector(common.StorageProtocolISCSI, "iscsi_volume", `{"multipath": true, "device_path": "/dev/dm-2", "targets": [{"iqn": "iqn.a", "portal": "10.0.0.1"}]}`)
+	writeConnector(common.StorageProtocolSAS, "sas_volume", `{"multipath": true, "volume_wwn": "600C0FF0005149ED8E2C7C6502000000", "scsi_devices": ["/dev/sdd"]}`)
+	writeConnector(common.StorageProtocolISCSI, "single_volume", `{"multipath": false, "device_path": "/dev/sdg"}`)
+	writeConnector(common.StorageProtocolFC, "unknown_volume", `{"multipath": true, "os_device_path": "/dev/dm-4"}`)
+	g.Expect(os.WriteFile(filepath.Join(runPath, "fscheck-iscsi_volume.json"), []byte(`{}`), 0644)).To(Succeed())
+
+	runner.On("multipathd show paths", `uuid                              dev dm_st  chk_st
+3600c0ff0005149ed8e2c7c6501000000 sdb active ready
+3600c0ff0005149ed8e2c7c6501000000 sdc failed faulty
+3600c0ff0005149ed8e2c7c6502000000 sdd active ready
+3600c0ff0005149ed8e2c7c6502000000 sde active ghost
+3600c0ff0005149ed8e2c7c6504000000 sdf active ready
+`, 0)
+
+	// the expected number of paths is configured per protocol rather than taken from the connector
+	expectedPaths := map[string]int{common.StorageProtocolISCSI: 2, common.StorageProtocolFC: 4}
+	monitor := &multipathMonitor{runPath: runPath, expectedPaths: expectedPaths, exported: map[string]string{}, degraded: map[string]bool{}}
+	volumes, err := monitor.check()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(runner.Commands()).To(Equal([]string{"multipathd show paths format %w %d %t %T"}))
+	g.Expect(volumes).To(ConsistOf(
+		&VolumePaths{VolumeName: "iscsi_volume", Protocol: "iscsi", WWN: "600c0ff0005149ed8e2c7c6501000000", Active: 1, Failed: 1, Expected: 2},
+		&VolumePaths{VolumeName: "sas_volume", Protocol: "sas", WWN: "600c0ff0005149ed8e2c7c6502000000", Active: 1, Ghost: 1},
+	))
+	g.Expect(volumes[0].Degraded()).To(BeTrue())
+	// without an expected number of paths a volume is degraded only once no path is active
+	g.Expect(monitor.degraded).To(Equal(map[string]bool{"iscsi_volume": true, "sas_volume": false}))
+
+	// detached volumes are no longer exported
+	g.Expect(os.Remove(ConnectorInfoPath(runPath, common.StorageProtocolSAS, "sas_volume"))).To(Succeed())
+	volumes, err = monitor.check()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(volumes).To(HaveLen(1))
+	g.Expect(monitor.exported).To(Equal(map[string]string{"iscsi_volume": "iscsi"}))
+}
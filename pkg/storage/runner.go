@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"k8s.io/klog/v2"
+)
+
+// CommandRunner runs the host commands of the node plugin (blkid, mount, multipath, mkfs, ...).
+// The default runner executes them, other runners can record them for tests or only log them.
+type CommandRunner interface {
+	// CombinedOutput runs the command and returns its combined standard output and standard error
+	CombinedOutput(cmd *Command) ([]byte, error)
+	// Output runs the command and returns its standard output
+	Output(cmd *Command) ([]byte, error)
+	// WriteFile writes to a sysfs or procfs attribute, such as a device rescan file, or to a record of the node
+	WriteFile(path string, data string) error
+	// Mkdir creates a directory, such as the target path of a filesystem volume
+	Mkdir(path string, perm os.FileMode) error
+	// CreateFile creates an empty file, such as the target path of a block volume
+	CreateFile(path string, perm os.FileMode) error
+	// Remove removes a file or an empty directory, such as an unmounted target path
+	Remove(path string) error
+	// SetGroup gives a group ownership of a directory, with group read/write/execute and setgid
+	SetGroup(path string, gid int) error
+}
+
+// Command is a host command to be run by the configured CommandRunner
+type Command struct {
+	Name  string
+	Args  []string
+	Stdin string
+
+	ctx context.Context
+}
+
+var commandRunner CommandRunner = &ExecRunner{}
+
+// SetCommandRunner: Replace the runner used for all host commands
+func SetCommandRunner(runner CommandRunner) {
+	commandRunner = runner
+}
+
+// NewCommand: Create a host command, in the same way as exec.Command
+func NewCommand(name string, args ...string) *Command {
+	return NewCommandContext(context.Background(), name, args...)
+}
+
+// NewCommandContext: Create a host command which is killed when the context is done, in the same way as exec.CommandContext
+func NewCommandContext(ctx context.Context, name string, args ...string) *Command {
+	return &Command{Name: name, Args: args, ctx: ctx}
+}
+
+// String returns the command line, for logging
+func (cmd *Command) String() string {
+	return strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")
+}
+
+// CombinedOutput runs the command with the configured runner and returns its combined output
+func (cmd *Command) CombinedOutput() ([]byte, error) {
+	return commandRunner.CombinedOutput(cmd)
+}
+
+// Output runs the command with the configured runner and returns its standard output
+func (cmd *Command) Output() ([]byte, error) {
+	return commandRunner.Output(cmd)
+}
+
+// WriteSysfs: Write to a sysfs attribute with the configured runner
+func WriteSysfs(path string, data string) error {
+	return commandRunner.WriteFile(path, data)
+}
+
+// ExitCode: Return the exit code of a command that ran but failed. The second value is false if the
+// command could not be run at all.
+func ExitCode(err error) (int, bool) {
+	if exitErr, ok := err.(interface{ ExitCode() int }); ok {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// ExecRunner runs commands on the host
+type ExecRunner struct{}
+
+func (runner *ExecRunner) command(cmd *Command) *exec.Cmd {
+	execCmd := exec.CommandContext(cmd.ctx, cmd.Name, cmd.Args...)
+	if cmd.Stdin != "" {
+		execCmd.Stdin = strings.NewReader(cmd.Stdin)
+	}
+	return execCmd
+}
+
+func (runner *ExecRunner) CombinedOutput(cmd *Command) ([]byte, error) {
+	return runner.command(cmd).CombinedOutput()
+}
+
+func (runner *ExecRunner) Output(cmd *Command) ([]byte, error) {
+	return runner.command(cmd).Output()
+}
+
+func (runner *ExecRunner) WriteFile(path string, data string) error {
+	return os.WriteFile(path, []byte(data), 0644)
+}
+
+func (runner *ExecRunner) Mkdir(path string, perm os.FileMode) error {
+	return os.Mkdir(path, perm)
+}
+
+func (runner *ExecRunner) CreateFile(path string, perm os.FileMode) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Chmod(perm)
+}
+
+func (runner *ExecRunner) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (runner *ExecRunner) SetGroup(path string, gid int) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Gid) == gid && info.Mode()&os.ModeSetgid != 0 {
+		klog.V(2).InfoS("directory already owned by group", "path", path, "gid", gid)
+		return nil
+	}
+	if err := os.Lchown(path, -1, gid); err != nil {
+		return err
+	}
+	// Same permissions kubelet applies for an fsGroup: group read/write/execute, and setgid so new files inherit the group
+	return os.Chmod(path, info.Mode().Perm()|0070|os.ModeSetgid)
+}
+
+// DryRunRunner logs the commands it would run and the files it would change without touching the host.
+// Every command succeeds, with no output except for the size queries, which report 0 bytes.
+type DryRunRunner struct{}
+
+// dryRunOutputs are the outputs of the dry-run commands whose output is parsed, by command line prefix
+var dryRunOutputs = map[string]string{
+	"blockdev --getsize64":                       "0\n",
+	"findmnt --bytes --noheadings --output SIZE": "0\n",
+}
+
+func (runner *DryRunRunner) CombinedOutput(cmd *Command) ([]byte, error) {
+	klog.InfoS("[DRY RUN] command not run", "command", cmd.String())
+	commandLine := cmd.String()
+	for prefix, output := range dryRunOutputs {
+		if strings.HasPrefix(commandLine, prefix) {
+			return []byte(output), nil
+		}
+	}
+	return nil, nil
+}
+
+func (runner *DryRunRunner) Output(cmd *Command) ([]byte, error) {
+	return runner.CombinedOutput(cmd)
+}
+
+func (runner *DryRunRunner) WriteFile(path string, data string) error {
+	klog.InfoS("[DRY RUN] file not written", "path", path, "data", data)
+	return nil
+}
+
+func (runner *DryRunRunner) Mkdir(path string, perm os.FileMode) error {
+	klog.InfoS("[DRY RUN] directory not created", "path", path, "perm", perm)
+	return nil
+}
+
+func (runner *DryRunRunner) CreateFile(path string, perm os.FileMode) error {
+	klog.InfoS("[DRY RUN] file not created", "path", path, "perm", perm)
+	return nil
+}
+
+func (runner *DryRunRunner) Remove(path string) error {
+	klog.InfoS("[DRY RUN] file not removed", "path", path)
+	return nil
+}
+
+func (runner *DryRunRunner) SetGroup(path string, gid int) error {
+	klog.InfoS("[DRY RUN] group not applied", "path", path, "gid", gid)
+	return nil
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FakeRunner records the commands it is asked to run and answers them with canned results, for tests
+type FakeRunner struct {
+	mu       sync.Mutex
+	commands []string
+	results  []fakeResult
+}
+
+type fakeResult struct {
+	prefix   string
+	output   string
+	exitCode int
+}
+
+// FakeExitError is returned by FakeRunner for a command with a non-zero exit code
+type FakeExitError struct {
+	Code int
+}
+
+func (e *FakeExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *FakeExitError) ExitCode() int {
+	return e.Code
+}
+
+// NewFakeRunner: Create a runner where every command succeeds with no output until told otherwise
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// On: Answer commands whose command line starts with prefix with the given output and exit code.
+// Results registered later take precedence.
+func (runner *FakeRunner) On(prefix string, output string, exitCode int) *FakeRunner {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	runner.results = append([]fakeResult{{prefix: prefix, output: output, exitCode: exitCode}}, runner.results...)
+	return runner
+}
+
+// Commands: Return the command lines run so far, in order
+func (runner *FakeRunner) Commands() []string {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	return append([]string{}, runner.commands...)
+}
+
+func (runner *FakeRunner) CombinedOutput(cmd *Command) ([]byte, error) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	commandLine := cmd.String()
+	runner.commands = append(runner.commands, commandLine)
+	for _, result := range runner.results {
+		if strings.HasPrefix(commandLine, result.prefix) {
+			if result.exitCode != 0 {
+				return []byte(result.output), &FakeExitError{Code: result.exitCode}
+			}
+			return []byte(result.output), nil
+		}
+	}
+	return nil, nil
+}
+
+func (runner *FakeRunner) Output(cmd *Command) ([]byte, error) {
+	return runner.CombinedOutput(cmd)
+}
+
+// WriteFile records the write as "write <path> <data>" without touching the file
+func (runner *FakeRunner) WriteFile(path string, data string) error {
+	return runner.record("write %s %s", path, data)
+}
+
+// Mkdir records the call as "mkdir <path>" without creating the directory
+func (runner *FakeRunner) Mkdir(path string, perm os.FileMode) error {
+	return runner.record("mkdir %s", path)
+}
+
+// CreateFile records the call as "create <path>" without creating the file
+func (runner *FakeRunner) CreateFile(path string, perm os.FileMode) error {
+	return runner.record("create %s", path)
+}
+
+// Remove records the call as "remove <path>" without removing the file
+func (runner *FakeRunner) Remove(path string) error {
+	return runner.record("remove %s", path)
+}
+
+// SetGroup records the call as "chgrp <gid> <path>" without changing the directory
+func (runner *FakeRunner) SetGroup(path string, gid int) error {
+	return runner.record("chgrp %d %s", gid, path)
+}
+
+func (runner *FakeRunner) record(format string, args ...interface{}) error {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	runner.commands = append(runner.commands, fmt.Sprintf(format, args...))
+	return nil
+}
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -132,6 +131,18 @@ func (sas *sasStorage) AttachStorage(ctx context.Context, req *csi.NodePublishVo
 		return path, status.Error(codes.Unavailable, err.Error())
 	}
 	klog.InfoS("attached device", "path", path)
+	if !connector.Multipath {
+		// the map of the paths the attach rescan just found may still be forming, attach again once it shows up
+		if err := waitForAttachedMultipath(ctx, wwn); err != nil {
+			klog.InfoS("multipath device not found, using the attached device", "path", path, "err", err)
+		} else {
+			multipath := saslib.Connector{VolumeWWN: wwn}
+			if multipathPath, err := saslib.Attach(ctx, &multipath, &saslib.OSioHandler{}); err == nil {
+				connector, path = multipath, multipathPath
+				klog.InfoS("attached multipath device", "path", path)
+			}
+		}
+	}
 	err = connector.Persist(ctx, sas.connectorInfoPath)
 	return path, err
 }
@@ -148,11 +159,15 @@ func (sas *sasStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpublish
 	}
 	klog.InfoS("connector.OSPathName", "connector.OSPathName", connector.OSPathName)
 
-	if IsVolumeInUse(connector.OSPathName) {
+	if IsVolumeInUse(connector.OSPathName) || IsEncryptedDeviceInUse(req.GetVolumeId()) {
 		klog.InfoS("volume is still in use on the node, thus it will not be detached")
 		return nil
 	}
 
+	if err := CloseEncryptedDevice(req.GetVolumeId()); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
 	_, err = os.Stat(connector.OSPathName)
 	if err != nil && errors.Is(err, fs.ErrNotExist) {
 		klog.ErrorS(err, "assuming that volume is already disconnected")
@@ -161,7 +176,7 @@ func (sas *sasStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpublish
 
 	wwn, _ := common.VolumeIdGetWwn(req.GetVolumeId())
 	diskByIdPath := fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)
-	out, err := exec.Command("ls", "-l", diskByIdPath).CombinedOutput()
+	out, err := NewCommand("ls", "-l", diskByIdPath).CombinedOutput()
 	klog.InfoS("check for dm-name", "command", fmt.Sprintf("ls -l %s, err = %v, out = \n%s", diskByIdPath, err, string(out)))
 
 	if !connector.Multipath {
@@ -189,6 +204,9 @@ func (sas *sasStorage) DetachStorage(ctx context.Context, req *csi.NodeUnpublish
 		klog.ErrorS(err, "error detaching FC connection")
 		return err
 	}
+	if err := WaitForDeviceRemoval(ctx, wwn, connector.OSPathName, diskByIdPath); err != nil {
+		klog.ErrorS(err, "device still present after detach", "device", connector.OSPathName)
+	}
 
 	klog.InfoS("deleting SAS connection info file", "sas.connectorInfoPath", sas.connectorInfoPath)
 	os.Remove(sas.connectorInfoPath)
@@ -239,19 +257,13 @@ func (sas *sasStorage) NodeExpandVolume(ctx context.Context, req *csi.NodeExpand
 			return nil, err
 		}
 	} else {
-		klog.V(2).Info("device is NOT using multipath")
-	}
-
-	if req.GetVolumeCapability().GetMount() != nil {
-		klog.Infof("expanding filesystem using resize2fs on device %s", connector.OSPathName)
-		output, err := exec.Command("resize2fs", connector.OSPathName).CombinedOutput()
-		if err != nil {
-			klog.V(2).InfoS("could not resize filesystem", "resize2fs output", output)
-			return nil, fmt.Errorf("could not resize filesystem: %v", output)
+		klog.V(2).Info("device is NOT using multipath, rescanning devices")
+		if err := RescanDevices(connector.OSDevicePaths); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
-	return &csi.NodeExpandVolumeResponse{}, nil
+	return NodeExpandFilesystem(ctx, req, connector.OSPathName)
 }
 
 // NodeGetCapabilities returns the supported capabilities of the node server
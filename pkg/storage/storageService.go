@@ -22,8 +22,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +41,10 @@ import (
 const (
 	SASAddressFilePath = "/etc/kubernetes/sas-addresses"
 	FCAddressFilePath  = "/etc/kubernetes/fc-addresses"
+
+	// how long to wait for a device to report its new size after expansion
+	maxResizeAttempts = 30
+	resizeDelay       = 2
 )
 
 type StorageOperations interface {
@@ -78,27 +84,82 @@ func buildCommonService(config map[string]string) (commonService, error) {
 	return commonserv, nil
 }
 
+// storageFactory creates the storage implementation of each protocol and answers the host queries which depend on
+// how volumes are attached. It is replaced as a whole by UseFakeStorage and UseDryRunStorage.
+type storageFactory interface {
+	// newStorage returns the storage implementation of a protocol
+	newStorage(storageProtocol string, cs commonService, connectorInfoPath string) StorageOperations
+	// initiators returns the initiators of this node for a protocol
+	initiators(storageProtocol string) ([]string, error)
+	// rescanLUN probes for a newly mapped LUN, see RescanLUN
+	rescanLUN(storageProtocol string, lun int) ([]string, error)
+	// startMonitors watches the devices of the attached volumes, see StartMonitors
+	startMonitors(runPath string, expectedPaths map[string]int)
+	// usedLUNs counts the LUNs taken by volumes the driver did not attach, see UsedLUNs
+	usedLUNs(runPath string) (int, error)
+}
+
+var nodeStorage storageFactory = &hostStorage{}
+
 // NewStorageNode : To return specific implementation of storage
 func NewStorageNode(storageProtocol string, config map[string]string) (StorageOperations, error) {
 	comnserv, err := buildCommonService(config)
 	if err == nil {
 		storageProtocol = strings.TrimSpace(storageProtocol)
 		klog.V(2).Infof("NewStorageNode for (%s)", storageProtocol)
-		if storageProtocol == common.StorageProtocolFC {
-			return &fcStorage{cs: comnserv, connectorInfoPath: config["connectorInfoPath"]}, nil
-		} else if storageProtocol == common.StorageProtocolSAS {
-			return &sasStorage{cs: comnserv, connectorInfoPath: config["connectorInfoPath"]}, nil
-		} else if storageProtocol == common.StorageProtocolISCSI {
-			return &iscsiStorage{cs: comnserv, connectorInfoPath: config["connectorInfoPath"]}, nil
-		} else {
-			klog.Warningf("Invalid or no storage protocol specified (%s)", storageProtocol)
-			klog.Warningf("Expecting storageProtocol (iscsi, fc, sas, etc) in StorageClass YAML. Default of (%s) used.", common.StorageProtocolISCSI)
-			return &iscsiStorage{cs: comnserv, connectorInfoPath: config["connectorInfoPath"]}, nil
-		}
+		return nodeStorage.newStorage(storageProtocol, comnserv, config["connectorInfoPath"]), nil
 	}
 	return nil, err
 }
 
+// hostStorage attaches volumes with the SCSI initiators of the host
+type hostStorage struct{}
+
+func (host *hostStorage) newStorage(storageProtocol string, cs commonService, connectorInfoPath string) StorageOperations {
+	if storageProtocol == common.StorageProtocolFC {
+		return &fcStorage{cs: cs, connectorInfoPath: connectorInfoPath}
+	} else if storageProtocol == common.StorageProtocolSAS {
+		return &sasStorage{cs: cs, connectorInfoPath: connectorInfoPath}
+	} else if storageProtocol == common.StorageProtocolISCSI {
+		return &iscsiStorage{cs: cs, connectorInfoPath: connectorInfoPath}
+	} else {
+		klog.Warningf("Invalid or no storage protocol specified (%s)", storageProtocol)
+		klog.Warningf("Expecting storageProtocol (iscsi, fc, sas, etc) in StorageClass YAML. Default of (%s) used.", common.StorageProtocolISCSI)
+		return &iscsiStorage{cs: cs, connectorInfoPath: connectorInfoPath}
+	}
+}
+
+func (host *hostStorage) initiators(storageProtocol string) ([]string, error) {
+	switch storageProtocol {
+	case common.StorageProtocolFC:
+		return GetFCInitiators()
+	case common.StorageProtocolSAS:
+		return GetSASInitiators()
+	default:
+		return GetISCSIInitiators()
+	}
+}
+
+func (host *hostStorage) rescanLUN(storageProtocol string, lun int) ([]string, error) {
+	return rescanSCSILUN(storageProtocol, lun)
+}
+
+func (host *hostStorage) startMonitors(runPath string, expectedPaths map[string]int) {
+	if err := StartDeviceWatcher(); err != nil {
+		klog.ErrorS(err, "block device events unavailable, falling back to polling")
+	}
+	if err := StartHealthMonitor(); err != nil {
+		klog.ErrorS(err, "kernel log unavailable, filesystem and SCSI errors will not be reported to the controller")
+	}
+	StartMultipathMonitor(runPath, expectedPaths)
+}
+
+// StartMonitors: Watch the devices of the attached volumes: block device events, kernel errors and multipath paths,
+// checked against the number of paths expected for each protocol
+func StartMonitors(runPath string, expectedPaths map[string]int) {
+	nodeStorage.startMonitors(runPath, expectedPaths)
+}
+
 // ValidateStorageProtocol: Verifies that a correct protocol is chosen or returns a valid default storage protocol.
 func ValidateStorageProtocol(storageProtocol string) string {
 	if storageProtocol == common.StorageProtocolFC || storageProtocol == common.StorageProtocolISCSI || storageProtocol == common.StorageProtocolSAS {
@@ -110,6 +171,11 @@ func ValidateStorageProtocol(storageProtocol string) string {
 	}
 }
 
+// GetInitiators: Return the initiators of this node for a storage protocol
+func GetInitiators(storageProtocol string) ([]string, error) {
+	return nodeStorage.initiators(storageProtocol)
+}
+
 // gateKeepers is a thread safe map indexed by volume name.
 var gatekeepers = common.NewStringLock()
 
@@ -134,25 +200,6 @@ func GetFsType(req *csi.NodePublishVolumeRequest) string {
 	return fsType
 }
 
-// CheckFs: Perform a file system validation
-func CheckFs(path string, fstype string, context string) error {
-
-	if IsVolumeInUse(path) {
-		klog.Infof("Volume already mounted, not performing FS check")
-		return nil
-	}
-
-	fsRepairCommand := "e2fsck"
-	if fstype == "xfs" {
-		fsRepairCommand = "xfs_repair"
-	}
-	klog.Infof("Checking filesystem (%s -n %s) [%s]", fsRepairCommand, path, context)
-	if out, err := exec.Command(fsRepairCommand, "-n", path).CombinedOutput(); err != nil {
-		return errors.New(string(out))
-	}
-	return nil
-}
-
 // Check for and remove any rediscovered iscsi devices that were previously unmapped
 // This is a common function for SAS and FC
 func CheckPreviouslyRemovedDevices(ctx context.Context) error {
@@ -175,7 +222,7 @@ func FindDeviceFormat(device string) (string, error) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), BlkidTimeout*time.Second)
 	defer cancel()
-	output, err := exec.CommandContext(ctx, "blkid",
+	output, err := NewCommandContext(ctx, "blkid",
 		"-p",
 		"-s", "TYPE",
 		"-s", "PTTYPE",
@@ -190,7 +237,7 @@ func FindDeviceFormat(device string) (string, error) {
 
 	if err != nil {
 		// blkid exit with code 2 if the specified token (TYPE/PTTYPE, etc) could not be found or if device could not be identified.
-		if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() == 2 {
+		if exitCode, ok := ExitCode(err); ok && exitCode == 2 {
 			klog.V(2).Infof("Device seems to be is unformatted (%v)", err)
 			return "", nil
 		}
@@ -216,15 +263,103 @@ func FindDeviceFormat(device string) (string, error) {
 	}
 
 	if partitionType != "" {
-		klog.V(2).Infof("Device %q seems to have a partition table type: %s", partitionType)
+		klog.V(2).Infof("Device %q seems to have a partition table type: %s", device, partitionType)
 		return "OTHER/PARTITIONS", nil
 	}
 
 	return filesystemType, nil
 }
 
+// GetMkfsArgs: Build the mkfs arguments for a filesystem from the volume context (StorageClass parameters)
+func GetMkfsArgs(fsType string, volumeContext map[string]string, label string) ([]string, error) {
+	isExt := strings.HasPrefix(fsType, "ext")
+	args := []string{}
+	if label != "" {
+		args = append(args, "-L", label)
+	}
+
+	unsupported := func(key string) error {
+		return status.Errorf(codes.InvalidArgument, "parameter %s is not supported for %s filesystems", key, fsType)
+	}
+	parsePositive := func(key string) (int, error) {
+		value, err := strconv.Atoi(volumeContext[key])
+		if err != nil || value <= 0 {
+			return 0, status.Errorf(codes.InvalidArgument, "parameter %s must be a positive number, got %q", key, volumeContext[key])
+		}
+		return value, nil
+	}
+	parseBool := func(key string) (bool, error) {
+		value, err := strconv.ParseBool(volumeContext[key])
+		if err != nil {
+			return false, status.Errorf(codes.InvalidArgument, "parameter %s must be true or false, got %q", key, volumeContext[key])
+		}
+		return value, nil
+	}
+
+	if _, ok := volumeContext[common.MkfsBlockSizeConfigKey]; ok {
+		blockSize, err := parsePositive(common.MkfsBlockSizeConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		if isExt {
+			args = append(args, "-b", strconv.Itoa(blockSize))
+		} else if fsType == "xfs" {
+			args = append(args, "-b", fmt.Sprintf("size=%d", blockSize))
+		} else {
+			return nil, unsupported(common.MkfsBlockSizeConfigKey)
+		}
+	}
+
+	if _, ok := volumeContext[common.MkfsInodeRatioConfigKey]; ok {
+		if !isExt {
+			return nil, unsupported(common.MkfsInodeRatioConfigKey)
+		}
+		inodeRatio, err := parsePositive(common.MkfsInodeRatioConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-i", strconv.Itoa(inodeRatio))
+	}
+
+	if _, ok := volumeContext[common.MkfsLazyInitConfigKey]; ok {
+		if !isExt {
+			return nil, unsupported(common.MkfsLazyInitConfigKey)
+		}
+		lazyInit, err := parseBool(common.MkfsLazyInitConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		value := 0
+		if lazyInit {
+			value = 1
+		}
+		args = append(args, "-E", fmt.Sprintf("lazy_itable_init=%d,lazy_journal_init=%d", value, value))
+	}
+
+	if _, ok := volumeContext[common.MkfsReflinkConfigKey]; ok {
+		if fsType != "xfs" {
+			return nil, unsupported(common.MkfsReflinkConfigKey)
+		}
+		reflink, err := parseBool(common.MkfsReflinkConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		value := 0
+		if reflink {
+			value = 1
+		}
+		args = append(args, "-m", fmt.Sprintf("reflink=%d", value))
+	}
+
+	return args, nil
+}
+
 // EnsureFsType:
-func EnsureFsType(fsType string, disk string) error {
+func EnsureFsType(fsType string, disk string, mkfsArgs []string) error {
+	if err := common.ValidateFsType(fsType); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	currentFsType, err := FindDeviceFormat(disk)
 	if err != nil {
 		return err
@@ -236,8 +371,9 @@ func EnsureFsType(fsType string, disk string) error {
 			return fmt.Errorf("Could not create %s filesystem on device %s since it already has one (%s)", fsType, disk, currentFsType)
 		}
 
-		klog.Infof("Creating %s filesystem on device %s", fsType, disk)
-		out, err := exec.Command(fmt.Sprintf("mkfs.%s", fsType), disk).CombinedOutput()
+		args := append(mkfsArgs, disk)
+		klog.Infof("Creating %s filesystem on device %s (mkfs.%s %s)", fsType, disk, fsType, strings.Join(args, " "))
+		out, err := NewCommand(fmt.Sprintf("mkfs.%s", fsType), args...).CombinedOutput()
 		if err != nil {
 			return errors.New(string(out))
 		}
@@ -246,29 +382,364 @@ func EnsureFsType(fsType string, disk string) error {
 	return nil
 }
 
-func MountFilesystem(req *csi.NodePublishVolumeRequest, path string) error {
+// ExpandFilesystem: Grow the filesystem on a device to fill the device, using the tool matching the filesystem type
+func ExpandFilesystem(devicePath string, mountPath string) error {
+	fsType, err := FindDeviceFormat(devicePath)
+	if err != nil {
+		return err
+	}
+
+	var cmd *Command
+	switch {
+	case fsType == "":
+		klog.InfoS("no filesystem found on device, skipping filesystem expansion", "device", devicePath)
+		return nil
+	case fsType == "xfs":
+		// xfs can only be grown while mounted, and xfs_growfs operates on the mount point
+		cmd = NewCommand("xfs_growfs", mountPath)
+	case strings.HasPrefix(fsType, "ext"):
+		cmd = NewCommand("resize2fs", devicePath)
+	case fsType == "btrfs":
+		cmd = NewCommand("btrfs", "filesystem", "resize", "max", mountPath)
+	default:
+		return fmt.Errorf("expansion of %s filesystem on device %s is not supported", fsType, devicePath)
+	}
+
+	klog.InfoS("expanding filesystem", "fsType", fsType, "command", cmd.String())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		klog.V(2).InfoS("could not resize filesystem", "fsType", fsType, "output", string(output))
+		return fmt.Errorf("could not resize %s filesystem: %s", fsType, output)
+	}
+	return nil
+}
+
+// GetDeviceSize: Return the size of a block device in bytes
+func GetDeviceSize(devicePath string) (int64, error) {
+	output, err := NewCommand("blockdev", "--getsize64", devicePath).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("could not get size of device %s: %s", devicePath, output)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// GetFilesystemSize: Return the size in bytes of the filesystem mounted on a path
+func GetFilesystemSize(mountPath string) (int64, error) {
+	output, err := NewCommand("findmnt", "--bytes", "--noheadings", "--output", "SIZE", "--mountpoint", mountPath).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("could not get size of filesystem mounted on %s: %s", mountPath, output)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// RescanDevices: Ask the kernel to re-read the capacity of each SCSI device, e.g. /dev/sdb
+func RescanDevices(devices []string) error {
+	for _, device := range devices {
+		rescanPath := fmt.Sprintf("/sys/block/%s/device/rescan", filepath.Base(device))
+		klog.V(2).InfoS("rescanning device", "device", device, "rescanPath", rescanPath)
+		if err := WriteSysfs(rescanPath, "1"); err != nil {
+			return fmt.Errorf("could not rescan device %s: %v", device, err)
+		}
+	}
+	return nil
+}
+
+// scsiDevicePath lists the SCSI devices of the node as host:channel:target:lun entries
+var scsiDevicePath = "/sys/class/scsi_device"
+
+// arrayWWNPrefix: volumes of Exos X and compatible arrays have a NAA WWN with the Seagate OUI
+const arrayWWNPrefix = "600c0ff"
+
+// arrayDevice: a SCSI device presenting an array volume through one path
+type arrayDevice struct {
+	host    string
+	channel string
+	target  string
+	lun     int
+	wwn     string
+}
+
+// getArrayDevices: Return the SCSI devices of the node which present array volumes
+func getArrayDevices() ([]arrayDevice, error) {
+	entries, err := os.ReadDir(scsiDevicePath)
+	if err != nil {
+		return nil, err
+	}
+	devices := []arrayDevice{}
+	for _, entry := range entries {
+		address := strings.Split(entry.Name(), ":")
+		if len(address) != 4 {
+			continue
+		}
+		lun, err := strconv.Atoi(address[3])
+		if err != nil {
+			continue
+		}
+		wwid, err := os.ReadFile(filepath.Join(scsiDevicePath, entry.Name(), "device", "wwid"))
+		if err != nil {
+			continue
+		}
+		wwn := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(string(wwid)), "naa."))
+		if strings.HasPrefix(wwn, arrayWWNPrefix) {
+			devices = append(devices, arrayDevice{host: address[0], channel: address[1], target: address[2], lun: lun, wwn: wwn})
+		}
+	}
+	return devices, nil
+}
+
+// UsedLUNs: Count the LUNs the node sees array volumes on which were not attached by the driver, such as volumes
+// mapped to the node outside of Kubernetes. These LUNs are unavailable to the driver, while the volumes it attached are
+// already counted by the scheduler.
+func UsedLUNs(runPath string) (int, error) {
+	return nodeStorage.usedLUNs(runPath)
+}
+
+// usedLUNs: LUNs are counted across every array the node reaches, which is conservative when it reaches several
+func (host *hostStorage) usedLUNs(runPath string) (int, error) {
+	devices, err := getArrayDevices()
+	if err != nil {
+		return 0, err
+	}
+	attached := map[string]bool{}
+	for _, volume := range attachedVolumes(runPath, nil) {
+		attached[volume.WWN] = true
+	}
+	luns := map[int]bool{}
+	for _, device := range devices {
+		// the driver never maps volumes to LUN 0
+		if device.lun > 0 && device.lun < common.MaximumLUN && !attached[device.wwn] {
+			luns[device.lun] = true
+		}
+	}
+	return len(luns), nil
+}
+
+// scsiHostPath lists the SCSI hosts of the node, writing "channel target lun" to their scan file probes for devices
+var scsiHostPath = "/sys/class/scsi_host"
+
+// protocolHostPaths list the SCSI hosts of each storage protocol
+var protocolHostPaths = map[string]string{
+	common.StorageProtocolISCSI: "/sys/class/iscsi_host",
+	common.StorageProtocolFC:    "/sys/class/fc_host",
+	common.StorageProtocolSAS:   "/sys/class/sas_host",
+}
+
+// RescanLUN: Probe for a newly mapped LUN on the node. Returns the scan requests written, as "host channel target lun".
+func RescanLUN(protocol string, lun int) ([]string, error) {
+	return nodeStorage.rescanLUN(protocol, lun)
+}
+
+// rescanSCSILUN: Probe for a newly mapped LUN through the SCSI targets of the protocol which already present array
+// volumes, and through every other SCSI host of the protocol, rather than rescanning every host and target.
+func rescanSCSILUN(protocol string, lun int) ([]string, error) {
+	entries, err := os.ReadDir(protocolHostPaths[protocol])
+	if err != nil {
+		return nil, err
+	}
+	hosts := map[string]bool{}
+	for _, entry := range entries {
+		hosts[strings.TrimPrefix(entry.Name(), "host")] = false
+	}
+	devices, err := getArrayDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	scans := map[string]string{}
+	for _, device := range devices {
+		if _, ok := hosts[device.host]; !ok {
+			continue
+		}
+		hosts[device.host] = true
+		scans[device.host+" "+device.channel+" "+device.target] = fmt.Sprintf("%s %s %d", device.channel, device.target, lun)
+	}
+	for host, presents := range hosts {
+		if !presents {
+			scans[host+" - -"] = fmt.Sprintf("- - %d", lun)
+		}
+	}
+
+	keys := make([]string, 0, len(scans))
+	for key := range scans {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	scanned := []string{}
+	for _, key := range keys {
+		host := strings.SplitN(key, " ", 2)[0]
+		scanFile := filepath.Join(scsiHostPath, "host"+host, "scan")
+		if err := WriteSysfs(scanFile, scans[key]); err != nil {
+			klog.ErrorS(err, "error scanning SCSI host", "scanFile", scanFile, "scan", scans[key])
+			continue
+		}
+		scanned = append(scanned, host+" "+scans[key])
+	}
+	klog.InfoS("rescanned LUN", "protocol", protocol, "lun", lun, "scans", scanned)
+	return scanned, nil
+}
+
+// WaitForDeviceSize: Wait until a block device reports at least the given size in bytes
+func WaitForDeviceSize(ctx context.Context, devicePath string, size int64) error {
+	var capacity int64
+	var err error
+	for attempt := 1; attempt <= maxResizeAttempts; attempt++ {
+		capacity, err = GetDeviceSize(devicePath)
+		klog.V(2).InfoS("waiting for device size", "device", devicePath, "attempt", attempt, "capacity", capacity, "size", size, "err", err)
+		if err == nil && capacity >= size {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return status.Errorf(codes.DeadlineExceeded, "device %s size (%d) did not reach the requested size (%d): %v", devicePath, capacity, size, ctx.Err())
+		case <-time.After(resizeDelay * time.Second):
+		}
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.Errorf(codes.Internal, "device %s size (%d) did not reach the requested size (%d) after %d attempts", devicePath, capacity, size, maxResizeAttempts)
+}
+
+// NodeExpandFilesystem: Grow the filesystem on an already resized device and verify the new capacity
+// This is a common function for iSCSI, FC and SAS
+func NodeExpandFilesystem(ctx context.Context, req *csi.NodeExpandVolumeRequest, devicePath string) (*csi.NodeExpandVolumeResponse, error) {
+	requiredBytes := req.GetCapacityRange().GetRequiredBytes()
+	if err := WaitForDeviceSize(ctx, devicePath, requiredBytes); err != nil {
+		return nil, err
+	}
+
+	filesystemDevicePath := devicePath
+	if mapperPath, exists := GetLuksMapperPath(req.GetVolumeId()); exists {
+		if err := ResizeEncryptedDevice(req.GetVolumeId(), req.GetSecrets()); err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		filesystemDevicePath = mapperPath
+	}
+
+	var capacity int64
+	var err error
+	if req.GetVolumeCapability().GetBlock() == nil {
+		if err = ExpandFilesystem(filesystemDevicePath, req.GetVolumePath()); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		// the filesystem is smaller than the device, report what the pods can use
+		capacity, err = GetFilesystemSize(req.GetVolumePath())
+	} else {
+		// the LUKS header takes some space, an encrypted block volume is the size of its mapping
+		capacity, err = GetDeviceSize(filesystemDevicePath)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	klog.InfoS("volume size after expansion", "device", devicePath, "capacity", capacity, "requiredBytes", requiredBytes)
+
+	return &csi.NodeExpandVolumeResponse{CapacityBytes: capacity}, nil
+}
+
+// IsReadOnly: Return true if the volume must be published read-only, either by request or by its access mode
+func IsReadOnly(req *csi.NodePublishVolumeRequest) bool {
+	return req.GetReadonly() || common.IsReadOnlyAccessMode(req.GetVolumeCapability().GetAccessMode().GetMode())
+}
+
+// GetMountOptions: Build the validated mount options for a publish request from its mount flags and read-only setting
+func GetMountOptions(req *csi.NodePublishVolumeRequest) ([]string, error) {
+	mountFlags := req.GetVolumeCapability().GetMount().GetMountFlags()
+	if err := common.ValidateMountFlags(mountFlags); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	options := []string{}
+	for _, flag := range mountFlags {
+		if IsReadOnly(req) && flag == "rw" {
+			continue
+		}
+		options = append(options, flag)
+	}
+	if IsReadOnly(req) {
+		options = append(options, "ro")
+	}
+	return options, nil
+}
+
+// ApplyVolumeMountGroup: Give the volume mount group ownership of the filesystem root, once, so kubelet
+// does not need to recursively change the ownership of the volume for the pod fsGroup
+func ApplyVolumeMountGroup(targetPath string, volumeMountGroup string) error {
+	gid, err := strconv.Atoi(volumeMountGroup)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid volume mount group %q: %v", volumeMountGroup, err)
+	}
+
+	klog.InfoS("applying volume mount group to filesystem root", "targetPath", targetPath, "gid", gid)
+	if err := commandRunner.SetGroup(targetPath, gid); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func MountFilesystem(req *csi.NodePublishVolumeRequest, path string, runPath string) error {
 	fsType := GetFsType(req)
-	err := EnsureFsType(fsType, path)
+	mountOptions, err := GetMountOptions(req)
+	if err != nil {
+		return err
+	}
+	fsCheckPolicy, err := GetFsCheckPolicy(req.GetVolumeContext())
+	if err != nil {
+		return err
+	}
+	volumeMountGroup := req.GetVolumeCapability().GetMount().GetVolumeMountGroup()
+
+	pvName := req.GetVolumeContext()[common.PVNameConfigKey]
+	if pvName == "" {
+		pvName, _ = common.VolumeIdGetName(req.GetVolumeId())
+	}
+	mkfsArgs, err := GetMkfsArgs(fsType, req.GetVolumeContext(), common.GetFsLabel(pvName, fsType))
+	if err != nil {
+		return err
+	}
+
+	err = EnsureFsType(fsType, path, mkfsArgs)
 	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	if err = CheckFs(path, fsType, "Publish"); err != nil {
+	if err = CheckFs(req.GetVolumeId(), path, fsType, fsCheckPolicy, IsReadOnly(req), "Publish", runPath); err != nil {
 		return err
 	}
 
-	out, err := exec.Command("findmnt", "--output", "TARGET", "--noheadings", path).Output()
-	mountpoints := strings.Split(strings.Trim(string(out), "\n"), "\n")
+	out, err := NewCommand("findmnt", "--output", "TARGET", "--noheadings", path).Output()
+	mountpoints := []string{}
+	for _, mountpoint := range strings.Split(string(out), "\n") {
+		if mountpoint != "" {
+			mountpoints = append(mountpoints, mountpoint)
+		}
+	}
 	if err != nil || len(mountpoints) == 0 {
-		klog.V(1).InfoS("mount", "command", fmt.Sprintf("mount -t %s %s %s", fsType, path, req.GetTargetPath()))
-		os.Mkdir(req.GetTargetPath(), 00755)
+		args := []string{"-t", fsType}
+		if len(mountOptions) > 0 {
+			args = append(args, "-o", strings.Join(mountOptions, ","))
+		}
+		args = append(args, path, req.GetTargetPath())
+		klog.V(1).InfoS("mount", "command", "mount "+strings.Join(args, " "))
+		commandRunner.Mkdir(req.GetTargetPath(), 00755)
 		if _, err = os.Stat(path); errors.Is(err, os.ErrNotExist) {
 			klog.InfoS("targetpath does not exist", "targetPath", req.GetTargetPath())
 		}
-		out, err = exec.Command("mount", "-t", fsType, path, req.GetTargetPath()).CombinedOutput()
+		out, err = NewCommand("mount", args...).CombinedOutput()
 		if err != nil {
 			return status.Error(codes.Internal, string(out))
 		}
+		if volumeMountGroup != "" && !IsReadOnly(req) {
+			if err = ApplyVolumeMountGroup(req.GetTargetPath(), volumeMountGroup); err != nil {
+				Unmount(req.GetTargetPath())
+				return err
+			}
+		}
 	} else if len(mountpoints) == 1 {
 		if mountpoints[0] == req.GetTargetPath() {
 			klog.InfoS("volume already mounted", "targetPath", req.GetTargetPath())
@@ -285,17 +756,24 @@ func MountFilesystem(req *csi.NodePublishVolumeRequest, path string) error {
 }
 
 func MountDevice(req *csi.NodePublishVolumeRequest, path string) error {
-	deviceFile, err := os.Create(req.GetTargetPath())
+	err := commandRunner.CreateFile(req.GetTargetPath(), 00755)
 	if err != nil {
 		klog.ErrorS(err, "could not create file", "TargetPath", req.GetTargetPath())
 		return err
 	}
-	deviceFile.Chmod(00755)
-	deviceFile.Close()
-	out, err := exec.Command("mount", "-o", "bind", path, req.GetTargetPath()).CombinedOutput()
+	out, err := NewCommand("mount", "-o", "bind", path, req.GetTargetPath()).CombinedOutput()
 	if err != nil {
 		return status.Error(codes.Internal, string(out))
 	}
+	if IsReadOnly(req) {
+		// A bind mount ignores "ro" on creation, so the read-only flag has to be applied with a remount
+		klog.V(1).InfoS("mount", "command", "mount -o remount,bind,ro "+req.GetTargetPath())
+		out, err = NewCommand("mount", "-o", "remount,bind,ro", req.GetTargetPath()).CombinedOutput()
+		if err != nil {
+			Unmount(req.GetTargetPath())
+			return status.Error(codes.Internal, string(out))
+		}
+	}
 	return nil
 }
 
@@ -306,10 +784,10 @@ func Unmount(path string) error {
 	if err == nil {
 		klog.InfoS("unmounting volume", "path", path)
 		klog.V(4).InfoS("mountpoint command", "command", "mountpoint "+path)
-		out, err := exec.Command("mountpoint", path).CombinedOutput()
+		out, err := NewCommand("mountpoint", path).CombinedOutput()
 		if err == nil {
 			klog.V(4).InfoS("umount command", "command", "umount -l "+path)
-			out, err := exec.Command("umount", "-l", path).CombinedOutput()
+			out, err := NewCommand("umount", "-l", path).CombinedOutput()
 			if err != nil {
 				return status.Error(codes.Internal, string(out))
 			}
@@ -317,7 +795,7 @@ func Unmount(path string) error {
 			klog.ErrorS(err, "assuming that volume is already unmounted", "mountpoint_output", out)
 		}
 
-		err = os.Remove(path)
+		err = commandRunner.Remove(path)
 		if err != nil && !os.IsNotExist(err) {
 			return status.Error(codes.Internal, err.Error())
 		}
@@ -329,24 +807,31 @@ func Unmount(path string) error {
 
 // IsVolumeInUse: Use findmnt to determine if the device path is mounted or not.
 func IsVolumeInUse(devicePath string) bool {
-	_, err := exec.Command("findmnt", devicePath).CombinedOutput()
+	_, err := NewCommand("findmnt", devicePath).CombinedOutput()
 	klog.Infof("isVolumeInUse: findmnt %s, err=%v", devicePath, err)
 	if err != nil {
-		if _, ok := err.(*exec.ExitError); ok {
+		if _, ok := ExitCode(err); ok {
 			return false
 		}
 	}
 	return true
 }
 
-// DebugCorruption: Display additional information for debugging
-func DebugCorruption(prefix, path string) {
-	out, err := exec.Command("ls", "-l", path).CombinedOutput()
+// DebugCorruption: Display additional information for debugging, and return it
+func DebugCorruption(prefix, path string) string {
+	var debug strings.Builder
+
+	out, err := NewCommand("ls", "-l", path).CombinedOutput()
 	klog.Infof("%s ls -l %s, err = %v, out = \n%s", prefix, path, err, string(out))
+	fmt.Fprintf(&debug, "ls -l %s, err = %v, out = \n%s\n", path, err, string(out))
 
-	out, err = exec.Command("multipath", "-ll", "-v2", path).CombinedOutput()
+	out, err = NewCommand("multipath", "-ll", "-v2", path).CombinedOutput()
 	klog.Infof("%s multipath -ll -v2 %s, err = %v, out = \n%s", prefix, path, err, string(out))
+	fmt.Fprintf(&debug, "multipath -ll -v2 %s, err = %v, out = \n%s\n", path, err, string(out))
 
-	out, err = exec.Command("ls", "-lR", "/dev/disk").CombinedOutput()
+	out, err = NewCommand("ls", "-lR", "/dev/disk").CombinedOutput()
 	klog.Infof("%s ls -lR /dev/disk, err = %v, out = \n%s", prefix, err, string(out))
+	fmt.Fprintf(&debug, "ls -lR /dev/disk, err = %v, out = \n%s\n", err, string(out))
+
+	return debug.String()
 }
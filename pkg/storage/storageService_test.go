@@ -0,0 +1,288 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func useFakeRunner(t *testing.T) *FakeRunner {
+	runner := NewFakeRunner()
+	SetCommandRunner(runner)
+	t.Cleanup(func() { SetCommandRunner(&ExecRunner{}) })
+	return runner
+}
+
+func TestFindDeviceFormat(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	runner.On("blkid", "DEVNAME=/dev/dm-1\nTYPE=xfs\n", 0)
+	g.Expect(FindDeviceFormat("/dev/dm-1")).To(Equal("xfs"))
+
+	runner.On("blkid", "", 2)
+	g.Expect(FindDeviceFormat("/dev/dm-1")).To(Equal(""))
+
+	runner.On("blkid", "", 4)
+	_, err := FindDeviceFormat("/dev/dm-1")
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(runner.Commands()).To(HaveLen(3))
+	g.Expect(runner.Commands()[0]).To(Equal("blkid -p -s TYPE -s PTTYPE -o export /dev/dm-1"))
+}
+
+func TestExpandFilesystem(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	runner.On("blkid", "TYPE=xfs\n", 0)
+	g.Expect(ExpandFilesystem("/dev/dm-1", "/mnt/target")).To(Succeed())
+	g.Expect(runner.Commands()).To(ContainElement("xfs_growfs /mnt/target"))
+
+	runner.On("blkid", "TYPE=ext4\n", 0)
+	g.Expect(ExpandFilesystem("/dev/dm-1", "/mnt/target")).To(Succeed())
+	g.Expect(runner.Commands()).To(ContainElement("resize2fs /dev/dm-1"))
+
+	runner.On("blkid", "TYPE=vfat\n", 0)
+	g.Expect(ExpandFilesystem("/dev/dm-1", "/mnt/target")).NotTo(Succeed())
+
+	runner.On("blkid", "TYPE=ext4\n", 0).On("resize2fs", "resize2fs: Bad magic number", 1)
+	g.Expect(ExpandFilesystem("/dev/dm-1", "/mnt/target")).NotTo(Succeed())
+}
+
+func TestGetFilesystemSize(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	runner.On("findmnt", "10724835328\n", 0)
+	g.Expect(GetFilesystemSize("/mnt/target")).To(Equal(int64(10724835328)))
+	g.Expect(runner.Commands()).To(Equal([]string{"findmnt --bytes --noheadings --output SIZE --mountpoint /mnt/target"}))
+
+	runner.On("findmnt", "", 1)
+	_, err := GetFilesystemSize("/mnt/target")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRescanDevices(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	g.Expect(RescanDevices([]string{"/dev/sdb", "/dev/sdc"})).To(Succeed())
+	g.Expect(runner.Commands()).To(Equal([]string{"write /sys/block/sdb/device/rescan 1", "write /sys/block/sdc/device/rescan 1"}))
+}
+
+func TestResizeEncryptedDevice(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	volumeId := "vol1##iscsi##600c0ff00029a6a4a4cbf26501000000"
+
+	err := ResizeEncryptedDevice(volumeId, nil)
+	g.Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	g.Expect(runner.Commands()).To(BeEmpty())
+
+	g.Expect(ResizeEncryptedDevice(volumeId, map[string]string{common.EncryptionPassphraseKey: "secret"})).To(Succeed())
+	g.Expect(runner.Commands()).To(Equal([]string{"cryptsetup resize exos-luks-vol1 --key-file=-"}))
+}
+
+func TestRunFsCheck(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	runFsCheck := func(path string, fsType string, repair bool) string {
+		result, _ := runFsCheck(path, fsType, repair)
+		return result
+	}
+
+	g.Expect(runFsCheck("/dev/dm-1", "ext4", false)).To(Equal(FsCheckResultClean))
+	runner.On("e2fsck -n", "", 4)
+	g.Expect(runFsCheck("/dev/dm-1", "ext4", false)).To(Equal(FsCheckResultCorrupted))
+	runner.On("e2fsck -p", "", 1)
+	g.Expect(runFsCheck("/dev/dm-1", "ext4", true)).To(Equal(FsCheckResultRepaired))
+	// operational errors say nothing about the filesystem
+	runner.On("e2fsck -n", "e2fsck: Device or resource busy", 8)
+	g.Expect(runFsCheck("/dev/dm-1", "ext4", false)).To(Equal(FsCheckResultFailed))
+	runner.On("xfs_repair -n", "", 1)
+	g.Expect(runFsCheck("/dev/dm-1", "xfs", false)).To(Equal(FsCheckResultCorrupted))
+}
+
+func TestCheckFs(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	runPath := t.TempDir()
+	volumeId := common.VolumeIdAugment("vol1", common.StorageProtocolISCSI, "600c0ff0005149ed8e2c7c6501000000")
+	recordPath := GetFsCheckRecordPath(runPath, volumeId)
+
+	// the device is not mounted yet
+	runner.On("findmnt", "", 1)
+	runner.On("e2fsck -n", "", 4)
+	runner.On("e2fsck -p", "", 1)
+	g.Expect(CheckFs(volumeId, "/dev/dm-1", "ext4", common.FsCheckPolicyRepair, false, "Publish", runPath)).To(Succeed())
+	g.Expect(runner.Commands()).To(ContainElement("e2fsck -p /dev/dm-1"))
+	// the record is written through the runner
+	g.Expect(runner.Commands()).To(ContainElement(HavePrefix("write " + recordPath + " ")))
+
+	// a read-only volume may be mounted elsewhere, so it is only checked
+	commands := len(runner.Commands())
+	err := CheckFs(volumeId, "/dev/dm-1", "ext4", common.FsCheckPolicyRepair, true, "Publish", runPath)
+	g.Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	g.Expect(runner.Commands()[commands:]).NotTo(ContainElement("e2fsck -p /dev/dm-1"))
+
+	// a check which could not be run fails the publish
+	runner.On("e2fsck -n", "e2fsck: Device or resource busy", 8)
+	err = CheckFs(volumeId, "/dev/dm-1", "ext4", common.FsCheckPolicyCheck, false, "Publish", runPath)
+	g.Expect(status.Code(err)).To(Equal(codes.Internal))
+
+	RemoveFsCheckRecord(runPath, volumeId)
+	g.Expect(runner.Commands()).To(ContainElement("remove " + recordPath))
+}
+
+func TestMountDevice(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	req := &csi.NodePublishVolumeRequest{
+		TargetPath:       "/var/lib/kubelet/pods/pod/volumeDevices/pv",
+		VolumeCapability: &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}},
+	}
+
+	g.Expect(MountDevice(req, "/dev/dm-3")).To(Succeed())
+	g.Expect(runner.Commands()).To(Equal([]string{
+		"create /var/lib/kubelet/pods/pod/volumeDevices/pv",
+		"mount -o bind /dev/dm-3 /var/lib/kubelet/pods/pod/volumeDevices/pv",
+	}))
+}
+
+func TestDryRunRunner(t *testing.T) {
+	g := NewWithT(t)
+	SetCommandRunner(&DryRunRunner{})
+	t.Cleanup(func() { SetCommandRunner(&ExecRunner{}) })
+
+	g.Expect(GetDeviceSize("/dev/dm-3")).To(BeZero())
+	g.Expect(GetFilesystemSize("/mnt/volume")).To(BeZero())
+	g.Expect(NewCommand("mount", "/dev/dm-3", "/mnt/volume").CombinedOutput()).To(BeEmpty())
+}
+
+func TestIsVolumeInUse(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	g.Expect(IsVolumeInUse("/dev/dm-1")).To(BeTrue())
+	runner.On("findmnt", "", 1)
+	g.Expect(IsVolumeInUse("/dev/dm-1")).To(BeFalse())
+}
+
+func TestGetMkfsArgs(t *testing.T) {
+	g := NewWithT(t)
+
+	args, err := GetMkfsArgs("ext4", map[string]string{
+		common.MkfsBlockSizeConfigKey:  "4096",
+		common.MkfsInodeRatioConfigKey: "65536",
+		common.MkfsLazyInitConfigKey:   "false",
+	}, "label")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]string{"-L", "label", "-b", "4096", "-i", "65536", "-E", "lazy_itable_init=0,lazy_journal_init=0"}))
+
+	args, err = GetMkfsArgs("xfs", map[string]string{
+		common.MkfsBlockSizeConfigKey: "4096",
+		common.MkfsReflinkConfigKey:   "true",
+	}, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]string{"-b", "size=4096", "-m", "reflink=1"}))
+
+	_, err = GetMkfsArgs("xfs", map[string]string{common.MkfsInodeRatioConfigKey: "65536"}, "")
+	g.Expect(err).To(HaveOccurred())
+	_, err = GetMkfsArgs("ext4", map[string]string{common.MkfsBlockSizeConfigKey: "-1"}, "")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRescanLUN(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+	scsiDevicePath, scsiHostPath = t.TempDir(), t.TempDir()
+	iscsiHostPath := protocolHostPaths[common.StorageProtocolISCSI]
+	protocolHostPaths[common.StorageProtocolISCSI] = t.TempDir()
+	t.Cleanup(func() {
+		scsiDevicePath, scsiHostPath = "/sys/class/scsi_device", "/sys/class/scsi_host"
+		protocolHostPaths[common.StorageProtocolISCSI] = iscsiHostPath
+	})
+	for _, host := range []string{"host2", "host3", "host4", "host5"} {
+		g.Expect(os.MkdirAll(filepath.Join(scsiHostPath, host), 0755)).To(Succeed())
+		if host != "host5" {
+			g.Expect(os.MkdirAll(filepath.Join(protocolHostPaths[common.StorageProtocolISCSI], host), 0755)).To(Succeed())
+		}
+	}
+	// without array volumes every host of the protocol is scanned
+	g.Expect(RescanLUN(common.StorageProtocolISCSI, 5)).To(Equal([]string{"2 - - 5", "3 - - 5", "4 - - 5"}))
+	g.Expect(runner.Commands()).To(ContainElement("write " + filepath.Join(scsiHostPath, "host4", "scan") + " - - 5"))
+
+	for name, wwid := range map[string]string{
+		"2:0:0:1": "naa.600c0ff00029a6a4a4cbf26501000000\n",
+		"3:0:1:1": "naa.600c0ff00029a6a4a4cbf26501000000\n",
+		"3:0:1:2": "naa.600c0ff00029a6a4a4cbf26502000000\n",
+		"4:0:0:0": "t10.ATA     QEMU HARDDISK\n",
+		"5:0:0:1": "naa.600c0ff00029a6a4a4cbf26503000000\n",
+	} {
+		device := filepath.Join(scsiDevicePath, name, "device")
+		g.Expect(os.MkdirAll(device, 0755)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(device, "wwid"), []byte(wwid), 0644)).To(Succeed())
+	}
+	commands := len(runner.Commands())
+
+	// otherwise the targets of the protocol presenting array volumes are, along with the hosts of the protocol
+	// presenting none, while hosts of other protocols are left alone
+	g.Expect(RescanLUN(common.StorageProtocolISCSI, 6)).To(Equal([]string{"2 0 0 6", "3 0 1 6", "4 - - 6"}))
+	g.Expect(runner.Commands()[commands:]).To(Equal([]string{
+		"write " + filepath.Join(scsiHostPath, "host2", "scan") + " 0 0 6",
+		"write " + filepath.Join(scsiHostPath, "host3", "scan") + " 0 1 6",
+		"write " + filepath.Join(scsiHostPath, "host4", "scan") + " - - 6",
+	}))
+}
+
+func TestUsedLUNs(t *testing.T) {
+	g := NewWithT(t)
+	runPath := t.TempDir()
+	scsiDevicePath = t.TempDir()
+	t.Cleanup(func() { scsiDevicePath = "/sys/class/scsi_device" })
+
+	for name, wwid := range map[string]string{
+		"2:0:0:0": "naa.600c0ff00029a6a4a4cbf26500000000\n",
+		"2:0:0:1": "naa.600c0ff00029a6a4a4cbf26501000000\n",
+		"3:0:1:1": "naa.600c0ff00029a6a4a4cbf26501000000\n",
+		"3:0:1:2": "naa.600c0ff00029a6a4a4cbf26502000000\n",
+		"3:0:1:3": "naa.600c0ff00029a6a4a4cbf26503000000\n",
+		"4:0:0:4": "t10.ATA     QEMU HARDDISK\n",
+	} {
+		device := filepath.Join(scsiDevicePath, name, "device")
+		g.Expect(os.MkdirAll(device, 0755)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(device, "wwid"), []byte(wwid), 0644)).To(Succeed())
+	}
+	// LUN 0 and devices of other vendors are not counted, nor is a LUN seen through several paths counted twice
+	g.Expect(UsedLUNs(runPath)).To(Equal(3))
+
+	// volumes attached by the driver are counted by the scheduler instead
+	connector := `{"multipath":true,"volume_wwn":"600c0ff00029a6a4a4cbf26502000000"}`
+	g.Expect(os.WriteFile(ConnectorInfoPath(runPath, common.StorageProtocolFC, "vol2"), []byte(connector), 0644)).To(Succeed())
+	g.Expect(UsedLUNs(runPath)).To(Equal(2))
+}
@@ -0,0 +1,389 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// netlink multicast groups of kobject uevents: raw kernel events and events re-broadcast by udevd
+	ueventKernelGroup = 1
+	ueventUdevGroup   = 2
+
+	ueventBufferSize  = 64 * 1024
+	ueventSocketSize  = 1024 * 1024
+	ueventReadTimeout = time.Second
+	udevMagic         = 0xfeedcafe
+
+	// devicePollInterval: Safety net re-check while waiting for events, in case one was missed
+	devicePollInterval = 10 * time.Second
+	// attachMultipathTimeout: How long an FC or SAS attach which found a single path waits for its multipath map
+	attachMultipathTimeout = 30 * time.Second
+)
+
+// sysfsPath is where the kernel exposes device attributes, used when an event carries no udev properties
+var sysfsPath = "/sys"
+
+// Uevent is a block device event received from the kernel or from udev
+type Uevent struct {
+	Action    string
+	DevPath   string
+	Subsystem string
+	DevName   string
+	DevType   string
+	WWN       string
+	Seqnum    string
+	// multipath path events: PATH_FAILED or PATH_REINSTATED, the "major:minor" of the path and the valid path count
+	DMAction     string
+	DMPath       string
+	DMValidPaths string
+}
+
+// DeviceWatcher dispatches block device uevents to the callers waiting on a WWN
+type DeviceWatcher struct {
+	mu          sync.Mutex
+	fd          int
+	stopped     bool
+	nextID      int
+	subscribers map[int]*deviceSubscription
+	devices     map[string]string
+	// sequence numbers of the latest path events, which are received from both the kernel and udev
+	pathEvents []string
+}
+
+type deviceSubscription struct {
+	wwn    string
+	events chan Uevent
+}
+
+var deviceWatcher *DeviceWatcher
+
+// newDeviceWatcher: Create a watcher which is only fed by dispatch, the netlink socket is opened by StartDeviceWatcher
+func newDeviceWatcher() *DeviceWatcher {
+	return &DeviceWatcher{
+		fd:          -1,
+		subscribers: map[int]*deviceSubscription{},
+		devices:     map[string]string{},
+	}
+}
+
+// StartDeviceWatcher: Listen to kernel and udev uevents so that attach and detach can wait on devices instead of polling
+func StartDeviceWatcher() error {
+	if deviceWatcher != nil {
+		return nil
+	}
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return fmt.Errorf("unable to open uevent socket: %v", err)
+	}
+	address := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: ueventKernelGroup | ueventUdevGroup}
+	if err := syscall.Bind(fd, address); err != nil {
+		syscall.Close(fd)
+		return fmt.Errorf("unable to bind uevent socket: %v", err)
+	}
+	// a larger buffer avoids dropping events while many paths of a multipath device show up at once
+	_ = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF, ueventSocketSize)
+	timeout := syscall.NsecToTimeval(ueventReadTimeout.Nanoseconds())
+	_ = syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &timeout)
+
+	watcher := newDeviceWatcher()
+	watcher.fd = fd
+	deviceWatcher = watcher
+	go watcher.run()
+	klog.InfoS("watching block device uevents")
+	return nil
+}
+
+// StopDeviceWatcher: Close the uevent socket, waits fall back to polling
+func StopDeviceWatcher() {
+	if deviceWatcher == nil {
+		return
+	}
+	deviceWatcher.mu.Lock()
+	deviceWatcher.stopped = true
+	deviceWatcher.mu.Unlock()
+}
+
+func (watcher *DeviceWatcher) run() {
+	defer syscall.Close(watcher.fd)
+	buffer := make([]byte, ueventBufferSize)
+	for {
+		watcher.mu.Lock()
+		stopped := watcher.stopped
+		watcher.mu.Unlock()
+		if stopped {
+			return
+		}
+		n, _, err := syscall.Recvfrom(watcher.fd, buffer, 0)
+		if err != nil {
+			if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			if errors.Is(err, syscall.ENOBUFS) {
+				// events were dropped, waiters notice the device on their next poll
+				klog.V(2).InfoS("uevent socket overrun, some events were lost")
+				continue
+			}
+			klog.ErrorS(err, "unable to read uevents, falling back to polling")
+			watcher.mu.Lock()
+			watcher.stopped = true
+			watcher.mu.Unlock()
+			return
+		}
+		if event, ok := parseUevent(buffer[:n]); ok {
+			watcher.dispatch(event)
+		}
+	}
+}
+
+// parseUevent: Decode a kernel ("ACTION@DEVPATH") or libudev netlink message, only block devices are returned
+func parseUevent(message []byte) (Uevent, bool) {
+	var properties []byte
+	if bytes.HasPrefix(message, []byte("libudev\x00")) {
+		if len(message) < 24 || binary.BigEndian.Uint32(message[8:12]) != udevMagic {
+			return Uevent{}, false
+		}
+		offset := binary.NativeEndian.Uint32(message[16:20])
+		length := binary.NativeEndian.Uint32(message[20:24])
+		if uint64(offset)+uint64(length) > uint64(len(message)) {
+			return Uevent{}, false
+		}
+		properties = message[offset : offset+length]
+	} else {
+		header := bytes.IndexByte(message, 0)
+		if header < 0 || !bytes.Contains(message[:header], []byte("@")) {
+			return Uevent{}, false
+		}
+		properties = message[header+1:]
+	}
+
+	env := map[string]string{}
+	for _, property := range bytes.Split(properties, []byte{0}) {
+		if key, value, found := strings.Cut(string(property), "="); found {
+			env[key] = value
+		}
+	}
+	if env["SUBSYSTEM"] != "block" {
+		return Uevent{}, false
+	}
+	event := Uevent{
+		Action:    env["ACTION"],
+		DevPath:   env["DEVPATH"],
+		Subsystem: env["SUBSYSTEM"],
+		DevName:   env["DEVNAME"],
+		DevType:   env["DEVTYPE"],
+		WWN:       ueventWWN(env),
+		Seqnum:    env["SEQNUM"],
+
+		DMAction:     env["DM_ACTION"],
+		DMPath:       env["DM_PATH"],
+		DMValidPaths: env["DM_NR_VALID_PATHS"],
+	}
+	if event.WWN == "" && event.Action != "remove" {
+		event.WWN = sysfsWWN(event.DevPath)
+	}
+	return event, true
+}
+
+// ueventWWN: Extract the volume WWN from the udev properties of a SCSI disk or a multipath map
+func ueventWWN(env map[string]string) string {
+	if uuid, found := strings.CutPrefix(env["DM_UUID"], "mpath-3"); found {
+		return strings.ToLower(uuid)
+	}
+	if name, found := strings.CutPrefix(env["DM_NAME"], "3"); found && env["DM_UUID"] == "" {
+		return strings.ToLower(name)
+	}
+	if wwn := env["ID_WWN_WITH_EXTENSION"]; wwn != "" {
+		return strings.ToLower(strings.TrimPrefix(wwn, "0x"))
+	}
+	if serial, found := strings.CutPrefix(env["ID_SERIAL"], "3"); found {
+		return strings.ToLower(serial)
+	}
+	return ""
+}
+
+// sysfsWWN: Read the WWN of a block device from sysfs, for kernel events which carry no udev properties
+func sysfsWWN(devPath string) string {
+	if devPath == "" {
+		return ""
+	}
+	device := filepath.Join(sysfsPath, devPath)
+	if uuid, err := os.ReadFile(filepath.Join(device, "dm", "uuid")); err == nil {
+		if wwn, found := strings.CutPrefix(strings.TrimSpace(string(uuid)), "mpath-3"); found {
+			return strings.ToLower(wwn)
+		}
+		return ""
+	}
+	if wwid, err := os.ReadFile(filepath.Join(device, "device", "wwid")); err == nil {
+		if wwn, found := strings.CutPrefix(strings.TrimSpace(string(wwid)), "naa."); found {
+			return strings.ToLower(wwn)
+		}
+	}
+	return ""
+}
+
+// dispatch: Hand an event to the subscribers of its WWN, remove events are matched through the devices seen before
+func (watcher *DeviceWatcher) dispatch(event Uevent) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if event.WWN == "" {
+		event.WWN = watcher.devices[event.DevPath]
+	}
+	if event.Action == "remove" {
+		delete(watcher.devices, event.DevPath)
+	} else if event.WWN != "" {
+		watcher.devices[event.DevPath] = event.WWN
+	}
+	if event.WWN == "" {
+		return
+	}
+	klog.V(4).InfoS("block device uevent", "action", event.Action, "device", event.DevName, "devpath", event.DevPath, "wwn", event.WWN)
+	if healthEvent, ok := multipathHealthEvent(event); ok && !slices.Contains(watcher.pathEvents, event.Seqnum) {
+		watcher.pathEvents = append(watcher.pathEvents, event.Seqnum)
+		if len(watcher.pathEvents) > 16 {
+			watcher.pathEvents = watcher.pathEvents[1:]
+		}
+		publishHealthEvent(healthEvent)
+	}
+	for _, subscription := range watcher.subscribers {
+		if subscription.wwn != event.WWN {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			// the waiter has a pending event already and will re-check the device anyway
+		}
+	}
+}
+
+// subscribe: Receive the events of a WWN until the returned function is called
+func (watcher *DeviceWatcher) subscribe(wwn string) (<-chan Uevent, func()) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.stopped {
+		return nil, func() {}
+	}
+	id := watcher.nextID
+	watcher.nextID++
+	subscription := &deviceSubscription{wwn: strings.ToLower(wwn), events: make(chan Uevent, 1)}
+	watcher.subscribers[id] = subscription
+	return subscription.events, func() {
+		watcher.mu.Lock()
+		defer watcher.mu.Unlock()
+		delete(watcher.subscribers, id)
+	}
+}
+
+// WaitForDevice: Wait until ready returns true, re-checking whenever a device with the WWN appears, changes or
+// disappears. The wait ends with the context deadline, or after the default timeout for a context without one.
+// refresh is run on every poll interval, e.g. to reload multipath maps.
+func WaitForDevice(ctx context.Context, wwn string, ready func() bool, refresh func()) error {
+	return deviceWatcher.wait(ctx, wwn, ready, refresh)
+}
+
+func (watcher *DeviceWatcher) wait(ctx context.Context, wwn string, ready func() bool, refresh func()) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxDmnameAttempts*dmnameDelay*time.Second)
+		defer cancel()
+	}
+
+	var events <-chan Uevent
+	interval := dmnameDelay * time.Second
+	if watcher != nil {
+		var unsubscribe func()
+		events, unsubscribe = watcher.subscribe(wwn)
+		defer unsubscribe()
+		if events != nil {
+			interval = devicePollInterval
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		if ready() {
+			klog.V(2).InfoS("device ready", "wwn", wwn, "duration", time.Since(start))
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return status.Errorf(codes.DeadlineExceeded, "device %s not ready after %v: %v", wwn, time.Since(start).Round(time.Second), ctx.Err())
+		case event := <-events:
+			klog.V(3).InfoS("device event", "wwn", wwn, "action", event.Action, "device", event.DevName)
+		case <-ticker.C:
+			if refresh != nil {
+				refresh()
+			}
+		}
+	}
+}
+
+// WaitForMultipathDevice: Wait until the multipath map of a WWN exists, reloading the multipath maps on every poll
+// interval. The controller asks the node to rescan right after mapping so the map usually shows up quickly.
+func WaitForMultipathDevice(ctx context.Context, wwn string) error {
+	dmName := fmt.Sprintf("/dev/disk/by-id/dm-name-3%s", wwn)
+	return WaitForDevice(ctx, wwn, func() bool {
+		out, err := NewCommand("ls", "-l", dmName).CombinedOutput()
+		klog.V(1).InfoS("check for dm-name exists", "command", fmt.Sprintf("ls -l %s", dmName), "err", err, "out", out)
+		return err == nil
+	}, func() {
+		// Force a reload of all existing multipath maps
+		output, err := NewCommand("multipath", "-r").CombinedOutput()
+		klog.V(4).InfoS("## (publish) multipath -r output", "err", err, "output", output)
+	})
+}
+
+// waitForAttachedMultipath: Wait a short while for the multipath map of a device the attach found as a single path,
+// bounded on its own so that a map which never forms does not use up the deadline of the publish request
+func waitForAttachedMultipath(ctx context.Context, wwn string) error {
+	ctx, cancel := context.WithTimeout(ctx, attachMultipathTimeout)
+	defer cancel()
+	return WaitForMultipathDevice(ctx, wwn)
+}
+
+// WaitForDeviceRemoval: Confirm a detach by waiting until none of the device paths of a WWN exist anymore
+func WaitForDeviceRemoval(ctx context.Context, wwn string, paths ...string) error {
+	return WaitForDevice(ctx, wwn, func() bool {
+		for _, path := range paths {
+			if _, err := os.Stat(path); err == nil {
+				return false
+			}
+		}
+		return true
+	}, nil)
+}
@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2026 Seagate Technology LLC and/or its Affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// For any questions about this software or licensing,
+// please email opensource@seagate.com or cortx-questions@seagate.com.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testWWN = "600c0ff0005149ed8e2c7c6501000000"
+
+func kernelUevent(header string, properties ...string) []byte {
+	return []byte(header + "\x00" + strings.Join(properties, "\x00") + "\x00")
+}
+
+func udevUevent(properties ...string) []byte {
+	payload := []byte(strings.Join(properties, "\x00") + "\x00")
+	message := make([]byte, 40)
+	copy(message, "libudev\x00")
+	binary.BigEndian.PutUint32(message[8:], udevMagic)
+	binary.NativeEndian.PutUint32(message[12:], 40)
+	binary.NativeEndian.PutUint32(message[16:], 40)
+	binary.NativeEndian.PutUint32(message[20:], uint32(len(payload)))
+	return append(message, payload...)
+}
+
+func TestParseUevent(t *testing.T) {
+	g := NewWithT(t)
+
+	sysfs := t.TempDir()
+	sysfsPath = sysfs
+	t.Cleanup(func() { sysfsPath = "/sys" })
+	devPath := "/devices/platform/host3/session1/target3:0:0/3:0:0:1/block/sdc"
+	g.Expect(os.MkdirAll(filepath.Join(sysfs, devPath, "device"), 0755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(sysfs, devPath, "device", "wwid"), []byte("naa."+strings.ToUpper(testWWN)+"\n"), 0644)).To(Succeed())
+
+	// kernel events carry no udev properties, the WWN comes from sysfs
+	event, ok := parseUevent(kernelUevent("add@"+devPath, "ACTION=add", "DEVPATH="+devPath, "SUBSYSTEM=block", "DEVNAME=sdc", "DEVTYPE=disk"))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event).To(Equal(Uevent{Action: "add", DevPath: devPath, Subsystem: "block", DevName: "sdc", DevType: "disk", WWN: testWWN}))
+
+	event, ok = parseUevent(udevUevent("ACTION=change", "DEVPATH=/devices/virtual/block/dm-2", "SUBSYSTEM=block", "DEVNAME=/dev/dm-2",
+		"DM_NAME=3"+testWWN, "DM_UUID=mpath-3"+testWWN))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.DevName).To(Equal("/dev/dm-2"))
+	g.Expect(event.WWN).To(Equal(testWWN))
+
+	event, ok = parseUevent(udevUevent("ACTION=add", "DEVPATH=/devices/sdd", "SUBSYSTEM=block", "ID_WWN=0x600c0ff0005149ed", "ID_WWN_WITH_EXTENSION=0x"+testWWN))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(event.WWN).To(Equal(testWWN))
+
+	_, ok = parseUevent(kernelUevent("add@/devices/net/eth1", "ACTION=add", "SUBSYSTEM=net"))
+	g.Expect(ok).To(BeFalse())
+	_, ok = parseUevent([]byte("libudev\x00short"))
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestWaitForDevice(t *testing.T) {
+	g := NewWithT(t)
+
+	watcher := newDeviceWatcher()
+	var present atomic.Bool
+	check := present.Load
+
+	// the device shows up on an event, well before the poll interval
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		watcher.dispatch(Uevent{Action: "add", DevPath: "/devices/other", WWN: "600c0ff0005149ed8e2c7c6502000000"})
+		present.Store(true)
+		watcher.dispatch(Uevent{Action: "add", DevPath: "/devices/sdc", WWN: testWWN})
+	}()
+	start := time.Now()
+	g.Expect(watcher.wait(ctx, testWWN, check, nil)).To(Succeed())
+	g.Expect(time.Since(start)).To(BeNumerically("<", devicePollInterval))
+	g.Expect(watcher.subscribers).To(BeEmpty())
+
+	// remove events carry no WWN, they are matched with the device seen before
+	removed := make(chan struct{})
+	go func() {
+		defer close(removed)
+		time.Sleep(100 * time.Millisecond)
+		present.Store(false)
+		watcher.dispatch(Uevent{Action: "remove", DevPath: "/devices/sdc"})
+	}()
+	g.Expect(watcher.wait(ctx, testWWN, func() bool { return !present.Load() }, nil)).To(Succeed())
+	<-removed
+	g.Expect(watcher.devices).NotTo(HaveKey("/devices/sdc"))
+
+	// the wait ends with the request deadline
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := watcher.wait(ctx, testWWN, func() bool { return false }, nil)
+	g.Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+}
+
+func TestWaitForMultipathDevice(t *testing.T) {
+	g := NewWithT(t)
+	runner := useFakeRunner(t)
+
+	runner.On("ls", "", 0)
+	g.Expect(WaitForMultipathDevice(context.Background(), testWWN)).To(Succeed())
+	g.Expect(runner.Commands()).To(Equal([]string{"ls -l /dev/disk/by-id/dm-name-3" + testWWN}))
+
+	// the multipath maps are reloaded until the map shows up or the wait ends
+	runner.On("ls", "", 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	g.Expect(status.Code(WaitForMultipathDevice(ctx, testWWN))).To(Equal(codes.DeadlineExceeded))
+}
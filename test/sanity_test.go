@@ -1,19 +1,46 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
+	"github.com/Seagate/seagate-exos-x-csi/pkg/common"
 	"github.com/Seagate/seagate-exos-x-csi/pkg/controller"
 	"github.com/Seagate/seagate-exos-x-csi/pkg/node"
-	"github.com/kubernetes-csi/csi-test/pkg/sanity"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/simulator"
+	"github.com/Seagate/seagate-exos-x-csi/pkg/storage"
+	"github.com/kubernetes-csi/csi-test/v5/pkg/sanity"
 )
 
-// Test starts the drivers in background and runs k8s sanity checks
+const (
+	secretsFile = "./secrets.yml"
+	nodeName    = "sanity-node"
+)
+
+// Test starts the drivers in background and runs k8s sanity checks. The checks run against the array
+// described by secrets.yml and config.yml when secrets.yml exists, otherwise they run hermetically
+// against a simulated array and a node using fake storage.
 func Test(t *testing.T) {
+	if _, err := os.Stat(secretsFile); err == nil {
+		testArray(t)
+	} else {
+		testHermetic(t)
+	}
+}
+
+// testArray runs the sanity checks against a real array, see sanity-go
+func testArray(t *testing.T) {
+	t.Setenv(common.NodeNameEnvVar, nodeName)
 	controllerSocketPath := "unix:///tmp/controller.sock"
 	nodeSocketPath := "unix:///tmp/node.sock"
 
 	ctrl := controller.New()
+	ctrl.SetNodeAddressResolver(localNodeService)
 	node := node.New()
 
 	go ctrl.Start(controllerSocketPath)
@@ -22,10 +49,102 @@ func Test(t *testing.T) {
 	go node.Start(nodeSocketPath)
 	defer node.Stop()
 
-	sanity.Test(t, &sanity.Config{
-		Address:                  nodeSocketPath,
-		ControllerAddress:        controllerSocketPath,
-		SecretsFile:              "./secrets.yml",
-		TestVolumeParametersFile: "./config.yml",
-	})
+	config := sanity.NewTestConfig()
+	config.Address = nodeSocketPath
+	config.ControllerAddress = controllerSocketPath
+	config.SecretsFile = secretsFile
+	config.TestVolumeParametersFile = "./config.yml"
+	sanity.Test(t, config)
+}
+
+// testHermetic runs the sanity checks against the array simulator, with a node backing volumes by files
+func testHermetic(t *testing.T) {
+	dir := t.TempDir()
+
+	sim := simulator.New()
+	defer sim.Close()
+
+	servicePort, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(common.NodeServicePortEnvVar, strconv.Itoa(servicePort))
+	t.Setenv(common.NodeServiceInsecureEnvVar, "true")
+	t.Setenv(common.NodeNameEnvVar, nodeName)
+	t.Setenv(common.NodeRunPathEnvVar, filepath.Join(dir, "run"))
+
+	storage.UseFakeStorage(dir)
+	defer storage.SetCommandRunner(&storage.ExecRunner{})
+
+	secretsPath := filepath.Join(dir, "secrets.yml")
+	if err := writeSecrets(secretsPath, sim); err != nil {
+		t.Fatal(err)
+	}
+
+	controllerSocketPath := "unix://" + filepath.Join(dir, "controller.sock")
+	nodeSocketPath := "unix://" + filepath.Join(dir, "node.sock")
+
+	ctrl := controller.New()
+	ctrl.SetNodeAddressResolver(localNodeService)
+	node := node.New()
+
+	go ctrl.Start(controllerSocketPath)
+	defer ctrl.Stop()
+
+	go node.Start(nodeSocketPath)
+	defer node.Stop()
+
+	config := sanity.NewTestConfig()
+	config.Address = nodeSocketPath
+	config.ControllerAddress = controllerSocketPath
+	config.SecretsFile = secretsPath
+	config.TargetPath = filepath.Join(dir, "target")
+	config.StagingPath = filepath.Join(dir, "staging")
+	config.TestVolumeParameters = map[string]string{
+		common.PoolConfigKey:   simulator.DefaultPool,
+		common.FsTypeConfigKey: "ext4",
+	}
+	sanity.Test(t, config)
+}
+
+// localNodeService resolves the node of the test to this host, where its node service runs
+func localNodeService(ctx context.Context, name string) (string, error) {
+	if name != nodeName {
+		return "", fmt.Errorf("node %s not found", name)
+	}
+	return "127.0.0.1", nil
+}
+
+// writeSecrets creates a secrets file pointing every CSI call to the simulated array
+func writeSecrets(path string, sim *simulator.Simulator) error {
+	secrets := ""
+	for _, name := range []string{
+		"CreateVolumeSecret",
+		"DeleteVolumeSecret",
+		"ControllerPublishVolumeSecret",
+		"ControllerUnpublishVolumeSecret",
+		"ControllerValidateVolumeCapabilitiesSecret",
+		"NodeStageVolumeSecret",
+		"NodePublishVolumeSecret",
+		"CreateSnapshotSecret",
+		"DeleteSnapshotSecret",
+		"ControllerExpandVolumeSecret",
+		"ListSnapshotsSecret",
+	} {
+		secrets += fmt.Sprintf("%s:\n  %s: %q\n  %s: %q\n  %s: %q\n", name,
+			common.UsernameSecretKey, sim.Username,
+			common.PasswordSecretKey, sim.Password,
+			common.APIAddressConfigKey, sim.URL())
+	}
+	return os.WriteFile(path, []byte(secrets), 0600)
+}
+
+// freePort returns a TCP port nothing is listening on, for the node service
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
 }